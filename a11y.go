@@ -0,0 +1,120 @@
+package linkt
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// The vendored copy of axe-core, see third_party/axe-core/README.md.
+//
+//go:embed third_party/axe-core
+var vendored embed.FS
+
+// Path of axe-core in the vendored files.
+const axePath = "third_party/axe-core/axe.min.js"
+
+// Runs axe-core on the page and returns the violations it finds as JSON.
+const axeRun = `axe.run(document, {resultTypes: ["violations"]}).then(r => r.violations)`
+
+// A rule of axe-core that the page violates.
+type Violation struct {
+	ID      string `json:"id"`
+	Impact  string `json:"impact"`
+	Help    string `json:"help"`
+	HelpURL string `json:"helpUrl"`
+	Nodes   []struct {
+		// selectors of the element, more than one if it is inside an iframe or
+		// shadow DOM
+		Target         []any  `json:"target"`
+		FailureSummary string `json:"failureSummary"`
+	} `json:"nodes"`
+}
+
+// Returns the source of axe-core from the file at path, or the vendored copy if
+// path is empty.
+func AxeSource(path string) (string, error) {
+	var data []byte
+	var err error
+	if path != "" {
+		data, err = os.ReadFile(path)
+	} else {
+		data, err = vendored.ReadFile(axePath)
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", errors.New("axe-core is not vendored, run make axe or specify axe.min.js with the axe option")
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Returns the findings for each element of the page that violates a rule. The
+// severity of a finding follows the impact of the violation.
+func (v Violation) Findings(link string) []Finding {
+	severity := NOTICE
+	switch v.Impact {
+	case "critical", "serious":
+		severity = ERROR
+	case "moderate":
+		severity = WARNING
+	}
+	findings := []Finding{}
+	for _, n := range v.Nodes {
+		selectors := []string{}
+		for _, t := range n.Target {
+			selectors = append(selectors, fmt.Sprint(t))
+		}
+		f := NewFinding(v.ID, severity, link, v.Help)
+		f.Selector = strings.Join(selectors, " >>> ")
+		f.Impact = v.Impact
+		f.HelpURL = v.HelpURL
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// Loads the current page in a browser, runs axe-core on it, and adds the violations
+// to the findings of the page. Only an internal HTML page is audited. The browser is
+// closed when ctx is done.
+func (crawler *Crawler) audit(ctx context.Context) error {
+	page := crawler.current
+	if page.doc == nil || page.response == nil || page.response.StatusCode < 200 || page.response.StatusCode > 299 {
+		return nil
+	}
+	options := crawler.options
+	link := page.request.URL.String()
+	ctx, cancel := NewBrowser(ctx, options)
+	defer cancel()
+	if err := chromedp.Run(ctx, crawler.auth.Browser(ctx)); err != nil {
+		return fmt.Errorf("error sharing credentials with the browser for %s: %w", link, err)
+	}
+	violations := []Violation{}
+	err := chromedp.Run(ctx,
+		navigate(link, options.Timeout),
+		crawler.prepare(link),
+		chromedp.Evaluate(crawler.axe, nil),
+		chromedp.Evaluate(axeRun, &violations, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+			return p.WithAwaitPromise(true)
+		}),
+	)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("error running axe-core on %s: %w", link, err)
+	}
+	for _, v := range violations {
+		crawler.current.findings = append(crawler.current.findings, v.Findings(link)...)
+	}
+	crawler.logger.Info("audited a page", "page", link, "violations", len(violations))
+	return nil
+}
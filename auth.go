@@ -0,0 +1,334 @@
+package linkt
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"golang.org/x/net/html"
+)
+
+// The credentials the crawler sends with each request to the hosts it is allowed to
+// authenticate with. Credentials are never sent to any other host.
+type Auth struct {
+	// headers sent with each request, including the Authorization header
+	headers http.Header
+	// hosts that receive the credentials
+	hosts Set[string, int]
+	jar   *cookiejar.Jar
+}
+
+// Returns the credentials specified with the header, cookie, cookie-jar,
+// basic-auth, and bearer options. The credentials are sent to the host of root and
+// the hosts specified with the auth-host option.
+func NewAuth(options *Options, root *url.URL) (*Auth, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	auth := &Auth{
+		headers: http.Header{},
+		hosts:   Set[string, int]{strings.ToLower(root.Hostname()): 0},
+		jar:     jar,
+	}
+	for _, h := range options.AuthHosts {
+		auth.hosts[strings.ToLower(h)] = 0
+	}
+
+	// headers are in the form <name>: <value>
+	for _, h := range options.Headers {
+		name, value, found := strings.Cut(h, ":")
+		if !found || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid header %q, expected <name>: <value>", h)
+		}
+		auth.headers.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	switch {
+	case options.BasicAuth != "" && options.Bearer != "":
+		return nil, fmt.Errorf("basic auth and bearer token cannot be used together")
+	case options.BasicAuth != "":
+		if !strings.Contains(options.BasicAuth, ":") {
+			return nil, fmt.Errorf("invalid basic auth, expected <user>:<password>")
+		}
+		token := base64.StdEncoding.EncodeToString([]byte(options.BasicAuth))
+		auth.headers.Set("Authorization", "Basic "+token)
+	case options.Bearer != "":
+		auth.headers.Set("Authorization", "Bearer "+options.Bearer)
+	}
+
+	// cookies are in the form <name>=<value> and are set for each host
+	cookies := []*http.Cookie{}
+	for _, c := range options.Cookies {
+		name, value, found := strings.Cut(c, "=")
+		if !found || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid cookie %q, expected <name>=<value>", c)
+		}
+		cookies = append(cookies, &http.Cookie{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
+	}
+	for h := range auth.hosts {
+		jar.SetCookies(&url.URL{Scheme: root.Scheme, Host: h, Path: "/"}, cookies)
+	}
+	if options.CookieJar != "" {
+		if err := auth.load(options.CookieJar); err != nil {
+			return nil, err
+		}
+	}
+	return auth, nil
+}
+
+// Loads the cookies from a file in the Netscape cookie jar format into the jar.
+// Cookies for a domain that does not match an allowed host are skipped.
+func (a *Auth) load(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening cookie jar: %w", err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		httpOnly := strings.HasPrefix(line, "#HttpOnly_")
+		line = strings.TrimPrefix(line, "#HttpOnly_")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// domain, include subdomains, path, secure, expiry, name, value
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return fmt.Errorf("invalid cookie on line %d of %s", n, path)
+		}
+		domain := strings.TrimPrefix(strings.ToLower(fields[0]), ".")
+		if !a.allowed(domain, fields[1] == "TRUE") {
+			continue
+		}
+		cookie := &http.Cookie{
+			Name:     fields[5],
+			Value:    fields[6],
+			Path:     fields[2],
+			Secure:   fields[3] == "TRUE",
+			HttpOnly: httpOnly,
+		}
+		if fields[1] == "TRUE" {
+			cookie.Domain = domain
+		}
+		if expiry, err := strconv.ParseInt(fields[4], 10, 64); err == nil && expiry > 0 {
+			cookie.Expires = time.Unix(expiry, 0)
+		}
+		scheme := "http"
+		if cookie.Secure {
+			scheme = "https"
+		}
+		a.jar.SetCookies(&url.URL{Scheme: scheme, Host: domain, Path: cookie.Path}, []*http.Cookie{cookie})
+	}
+	return scanner.Err()
+}
+
+// Returns true if a cookie for domain would be sent to an allowed host.
+func (a *Auth) allowed(domain string, subdomains bool) bool {
+	for h := range a.hosts {
+		if h == domain || (subdomains && strings.HasSuffix(h, "."+domain)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true if credentials may be sent to the host of link.
+func (a *Auth) Allowed(link *url.URL) bool {
+	return a.hosts.Contains(strings.ToLower(link.Hostname()))
+}
+
+// Adds the credential headers to req if its host is allowed, otherwise they are
+// removed from req.
+func (a *Auth) Apply(req *http.Request) {
+	if req.Header == nil {
+		req.Header = http.Header{}
+	}
+	for name, values := range a.headers {
+		if a.Allowed(req.URL) {
+			req.Header[name] = values
+		} else {
+			req.Header.Del(name)
+		}
+	}
+}
+
+// Logs in by submitting the login form on the page at link. The values of the
+// inputs in the form, such as a CSRF token, are submitted with fields, which are
+// in the form <name>=<value>. The session cookies are stored in the jar.
+func (a *Auth) Login(ctx context.Context, client *http.Client, link string, fields []string) error {
+	u, err := url.Parse(link)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	a.Apply(req)
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	doc, err := html.Parse(res.Body)
+	if err != nil {
+		return err
+	}
+	form := loginForm(doc)
+	if form == nil {
+		return fmt.Errorf("no form found on the login page %s", link)
+	}
+
+	// collect the values of the inputs in the form and override them with fields
+	values := url.Values{}
+	var collect func(n *html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "input" && attr(n, "name") != "" {
+			values.Set(attr(n, "name"), attr(n, "value"))
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			collect(c)
+		}
+	}
+	collect(form)
+	for _, f := range fields {
+		name, value, found := strings.Cut(f, "=")
+		if !found {
+			return fmt.Errorf("invalid login field %q, expected <name>=<value>", f)
+		}
+		values.Set(name, value)
+	}
+
+	// submit the form to its action
+	action, err := res.Request.URL.Parse(attr(form, "action"))
+	if err != nil {
+		return err
+	}
+	method := strings.ToUpper(attr(form, "method"))
+	if method == "" {
+		method = http.MethodPost
+	}
+	if method == http.MethodGet {
+		action.RawQuery = values.Encode()
+		req, err = http.NewRequestWithContext(ctx, method, action.String(), nil)
+	} else {
+		req, err = http.NewRequestWithContext(ctx, method, action.String(), strings.NewReader(values.Encode()))
+	}
+	if err != nil {
+		return err
+	}
+	if method != http.MethodGet {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	a.Apply(req)
+	res, err = client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 400 {
+		return fmt.Errorf("login failed with status %s", res.Status)
+	}
+	return nil
+}
+
+// Returns the actions that share the credentials with the browser. The cookies in
+// the jar are set for each allowed host, and the credential headers are added to
+// each request the browser sends to an allowed host.
+func (a *Auth) Browser(ctx context.Context) chromedp.Tasks {
+	tasks := chromedp.Tasks{network.Enable()}
+	for h := range a.hosts {
+		for _, scheme := range []string{"http", "https"} {
+			u := &url.URL{Scheme: scheme, Host: h, Path: "/"}
+			for _, c := range a.jar.Cookies(u) {
+				tasks = append(tasks, network.SetCookie(c.Name, c.Value).WithURL(u.String()))
+			}
+		}
+	}
+	if len(a.headers) == 0 {
+		return tasks
+	}
+	chromedp.ListenTarget(ctx, func(ev any) {
+		paused, ok := ev.(*fetch.EventRequestPaused)
+		if !ok {
+			return
+		}
+		go func() {
+			c := chromedp.FromContext(ctx)
+			continued := fetch.ContinueRequest(paused.RequestID)
+			if u, err := url.Parse(paused.Request.URL); err == nil && a.Allowed(u) {
+				headers := []*fetch.HeaderEntry{}
+				for name, value := range paused.Request.Headers {
+					if a.headers.Get(name) == "" {
+						headers = append(headers, &fetch.HeaderEntry{Name: name, Value: fmt.Sprint(value)})
+					}
+				}
+				for name := range a.headers {
+					headers = append(headers, &fetch.HeaderEntry{Name: name, Value: a.headers.Get(name)})
+				}
+				continued = continued.WithHeaders(headers)
+			}
+			continued.Do(cdp.WithExecutor(ctx, c.Target))
+		}()
+	})
+	return append(tasks, fetch.Enable())
+}
+
+// Returns the form that contains a password input in the tree rooted at doc. If
+// there is no such form, then the first form is returned, or nil.
+func loginForm(doc *html.Node) *html.Node {
+	var password *html.Node
+	var search func(n *html.Node)
+	search = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "input" && strings.EqualFold(attr(n, "type"), "password") {
+			password = n
+			return
+		}
+		for c := n.FirstChild; c != nil && password == nil; c = c.NextSibling {
+			search(c)
+		}
+	}
+	search(doc)
+	for n := password; n != nil; n = n.Parent {
+		if n.Type == html.ElementNode && n.Data == "form" {
+			return n
+		}
+	}
+	return find(doc, "form")
+}
+
+// Returns the first element named tag in the tree rooted at n, or nil.
+func find(n *html.Node, tag string) *html.Node {
+	if n.Type == html.ElementNode && n.Data == tag {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := find(c, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Returns the value of the attribute key of element n, or an empty string.
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package linkt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// Returns a context for a new browser that is configured with the options the
+// crawler received. Certificate errors are ignored if the insecure option is set or
+// a CA bundle is specified, since the browser cannot be given the bundle. The
+// browser uses the same proxy and host overrides as the crawler's HTTP client, and
+// is closed when parent is done.
+func NewBrowser(parent context.Context, options *Options) (context.Context, context.CancelFunc) {
+	opts := append([]chromedp.ExecAllocatorOption{}, chromedp.DefaultExecAllocatorOptions[:]...)
+	if options.Insecure || options.CABundle != "" {
+		opts = append(opts, chromedp.Flag("ignore-certificate-errors", true))
+	}
+	if proxy := browserProxy(options); proxy != "" {
+		opts = append(opts, chromedp.ProxyServer(proxy))
+	}
+	if len(options.Resolve) > 0 {
+		rules := []string{}
+		for _, r := range options.Resolve {
+			parts := strings.SplitN(r, ":", 3)
+			if len(parts) == 3 {
+				rules = append(rules, fmt.Sprintf("MAP %s:%s %s", parts[0], parts[1], parts[2]))
+			}
+		}
+		opts = append(opts, chromedp.Flag("host-resolver-rules", strings.Join(rules, ",")))
+	}
+	allocator, cancelAllocator := chromedp.NewExecAllocator(parent, opts...)
+	ctx, cancel := chromedp.NewContext(
+		allocator,
+		// Uncomment to see browser UI (headless=false)
+		// chromedp.WithDebugf(log.Printf),
+	)
+	return ctx, func() {
+		cancel()
+		cancelAllocator()
+	}
+}
+
+// Returns the proxy the browser should use, or an empty string if it should connect
+// directly.
+func browserProxy(options *Options) string {
+	if options.Proxy != "" {
+		return options.Proxy
+	}
+	for _, env := range []string{"HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy"} {
+		if proxy := os.Getenv(env); proxy != "" {
+			return proxy
+		}
+	}
+	return ""
+}
+
+// Returns an action that navigates to link and fails if the page does not load
+// within timeout.
+func navigate(link string, timeout time.Duration) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return chromedp.Navigate(link).Do(ctx)
+	})
+}
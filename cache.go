@@ -0,0 +1,188 @@
+package linkt
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A response stored in the cache.
+type Cached struct {
+	URL        string      `json:"url"`
+	Status     string      `json:"status"`
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Redirects  []Hop       `json:"redirects,omitempty"`
+	// the time the response was received or revalidated
+	Checked time.Time `json:"checked"`
+	// body of an internal page, kept to extract its links again
+	Body []byte `json:"body,omitempty"`
+}
+
+// An on-disk HTTP cache keyed by URL. Internal pages are revalidated with their
+// ETag and Last-Modified headers, and external links are not requested again until
+// their result is older than the TTL.
+type Cache struct {
+	dir string
+	ttl time.Duration
+}
+
+// Returns a cache that stores responses in directory dir.
+func NewCache(dir string, ttl time.Duration) (*Cache, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	return &Cache{dir: dir, ttl: ttl}, nil
+}
+
+// Returns the cached response for link, or nil if link is not cached.
+func (c *Cache) Get(link string) *Cached {
+	data, err := os.ReadFile(c.path(link))
+	if err != nil {
+		return nil
+	}
+	cached := &Cached{}
+	if err := json.Unmarshal(data, cached); err != nil || cached.URL != link {
+		return nil
+	}
+	return cached
+}
+
+// Stores the response res for link along with body, which is nil unless the page
+// is internal.
+func (c *Cache) Put(link string, res *http.Response, body []byte) error {
+	return c.save(&Cached{
+		URL:        link,
+		Status:     res.Status,
+		StatusCode: res.StatusCode,
+		Header:     res.Header,
+		Redirects:  Chain(res),
+		Checked:    time.Now(),
+		Body:       body,
+	})
+}
+
+// Marks the cached response as checked now and stores it.
+func (c *Cache) Touch(cached *Cached) error {
+	cached.Checked = time.Now()
+	return c.save(cached)
+}
+
+// Returns true if the cached response was checked within the TTL.
+func (c *Cache) Fresh(cached *Cached) bool {
+	return cached != nil && time.Since(cached.Checked) < c.ttl
+}
+
+// Writes the cached response to its file.
+func (c *Cache) save(cached *Cached) error {
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path(cached.URL), data, 0666)
+}
+
+// Returns the path of the file for link.
+func (c *Cache) path(link string) string {
+	return filepath.Join(c.dir, hash(link)+".json")
+}
+
+// Adds the headers to req that make it conditional on the cached response changing.
+// It returns false if the cached response cannot be revalidated.
+func (cached *Cached) Revalidate(req *http.Request) bool {
+	if cached == nil || cached.Body == nil {
+		return false
+	}
+	etag := cached.Header.Get("ETag")
+	modified := cached.Header.Get("Last-Modified")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if modified != "" {
+		req.Header.Set("If-Modified-Since", modified)
+	}
+	return etag != "" || modified != ""
+}
+
+// Returns the cached response as a response to req. The redirect chain is rebuilt
+// so it is reported like the original.
+func (cached *Cached) Response(req *http.Request) *http.Response {
+	final := req
+	if len(cached.Redirects) > 1 {
+		var previous *http.Response
+		for _, h := range cached.Redirects[:len(cached.Redirects)-1] {
+			u, err := url.Parse(h.URL)
+			if err != nil {
+				break
+			}
+			code, _ := strconv.Atoi(strings.Fields(h.Status + " 0")[0])
+			previous = &http.Response{
+				Status:     h.Status,
+				StatusCode: code,
+				Request:    &http.Request{URL: u, Response: previous},
+			}
+		}
+		if u, err := url.Parse(cached.Redirects[len(cached.Redirects)-1].URL); err == nil && previous != nil {
+			final = req.Clone(req.Context())
+			final.URL = u
+			final.Response = previous
+		}
+	}
+	return &http.Response{
+		Status:     cached.Status,
+		StatusCode: cached.StatusCode,
+		Header:     cached.Header.Clone(),
+		Body:       io.NopCloser(bytes.NewReader(cached.Body)),
+		Request:    final,
+	}
+}
+
+// Returns the response for page from the cache or by sending its request. An
+// external page checked within the TTL is not requested again. An internal page is
+// revalidated, and its cached body is reused if it did not change.
+func (crawler *Crawler) cached(page Page) (*http.Response, bool, error) {
+	cache := crawler.cache
+	if cache == nil {
+		res, err := crawler.do(page)
+		return res, false, err
+	}
+	link := page.request.URL.String()
+	cached := cache.Get(link)
+	if page.kind != Internal && cache.Fresh(cached) {
+		return cached.Response(page.request), true, nil
+	}
+	revalidate := page.kind == Internal && cached.Revalidate(page.request)
+	res, err := crawler.do(page)
+	if err != nil {
+		return nil, false, err
+	}
+	if revalidate && res.StatusCode == http.StatusNotModified {
+		drain(res)
+		if err := cache.Touch(cached); err != nil {
+			crawler.logger.Info("error updating the cache", "page", link, "error", err)
+		}
+		return cached.Response(page.request), true, nil
+	}
+	// keep the body of an internal HTML page so its links can be extracted again
+	var body []byte
+	if page.kind == Internal && res.StatusCode >= 200 && res.StatusCode <= 299 &&
+		strings.Contains(res.Header.Get("Content-Type"), "html") {
+		body, err = io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, false, err
+		}
+		res.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	if err := cache.Put(link, res, body); err != nil {
+		crawler.logger.Info("error writing to the cache", "page", link, "error", err)
+	}
+	return res, false, nil
+}
@@ -0,0 +1,77 @@
+package linkt
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCacheRevalidate(t *testing.T) {
+	external := map[string]int{}
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		external[r.URL.Path]++
+	}))
+	defer other.Close()
+	etag := `"v1"`
+	modified := "Wed, 01 Jan 2025 00:00:00 GMT"
+	// status of the response to each request for a path
+	statuses := map[string][]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := http.StatusOK
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("ETag", etag)
+			if r.Header.Get("If-None-Match") == etag {
+				status = http.StatusNotModified
+			}
+		case "/a":
+			w.Header().Set("Last-Modified", modified)
+			if r.Header.Get("If-Modified-Since") == modified {
+				status = http.StatusNotModified
+			}
+		}
+		statuses[r.URL.Path] = append(statuses[r.URL.Path], status)
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			fmt.Fprintf(w, `<a href="/a">a</a><a href="%s/x">x</a>`, other.URL)
+		}
+	}))
+	defer server.Close()
+	options := NewOptions(TEST)
+	options.Cache = t.TempDir()
+
+	// the first crawl fills the cache, the second revalidates the pages
+	for i := 0; i < 2; i++ {
+		crawler, urls := crawl(t, options, server.URL)
+		if len(urls) != 3 {
+			t.Fatalf("crawl %d tested %v, want the root, /a, and %s/x", i+1, urls, other.URL)
+		}
+		for _, r := range crawler.Records() {
+			if cached := strings.Contains(r.RequestTime, "cached"); cached != (i == 1) {
+				t.Errorf("crawl %d: request time of %s = %q", i+1, r.URL, r.RequestTime)
+			}
+		}
+	}
+	want := []int{http.StatusOK, http.StatusNotModified}
+	for _, p := range []string{"/", "/a"} {
+		if fmt.Sprint(statuses[p]) != fmt.Sprint(want) {
+			t.Errorf("responses for %s = %v, want %v", p, statuses[p], want)
+		}
+	}
+	if external["/x"] != 1 {
+		t.Errorf("the external link was requested %d times, want 1", external["/x"])
+	}
+
+	// a page that changed is not served from the cache
+	etag = `"v2"`
+	crawler, _ := crawl(t, options, server.URL)
+	if r := crawler.Records()[0]; strings.Contains(r.RequestTime, "cached") {
+		t.Errorf("request time of the changed root = %q", r.RequestTime)
+	}
+	if got := statuses["/"][len(statuses["/"])-1]; got != http.StatusOK {
+		t.Errorf("response for the changed root = %d, want %d", got, http.StatusOK)
+	}
+}
@@ -0,0 +1,151 @@
+package linkt
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+)
+
+// Kind of finding
+const CERT_EXPIRED = "certificate-expired"
+const CERT_EXPIRING = "certificate-expiring"
+const CERT_MISMATCH = "certificate-mismatch"
+const CERT_UNTRUSTED = "certificate-untrusted"
+
+// Represents a certificate in the chain a host presents.
+type Link struct {
+	Subject string    `json:"subject"`
+	Issuer  string    `json:"issuer"`
+	Expires time.Time `json:"expires"`
+}
+
+// Represents the TLS certificate of a host and the connection to it.
+type Certificate struct {
+	Host    string   `json:"host"`
+	Issuer  string   `json:"issuer"`
+	Names   []string `json:"names"`
+	Version string   `json:"version"`
+	// the earliest expiry of the certificates in the chain
+	Expires time.Time `json:"expires"`
+	Chain   []Link    `json:"chain"`
+	// true if the certificate is valid for the host
+	Matches bool `json:"matches"`
+	// true if the chain is signed by a trusted root
+	Trusted bool `json:"trusted"`
+}
+
+// Connects to the host of link with dial and returns its certificate. The
+// certificate is retrieved even if it is invalid so the problems with it can be
+// reported. The chain is verified against roots, or the system roots if roots is nil.
+// The connection is made directly to the host, not through a proxy, and is
+// abandoned when ctx is done.
+func Inspect(ctx context.Context, link *url.URL, roots *x509.CertPool, dial Dial, timeout time.Duration) (*Certificate, error) {
+	port := link.Port()
+	if port == "" {
+		port = "443"
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	raw, err := dial(ctx, "tcp", net.JoinHostPort(link.Hostname(), port))
+	if err != nil {
+		return nil, err
+	}
+	conn := tls.Client(raw, &tls.Config{
+		ServerName:         link.Hostname(),
+		InsecureSkipVerify: true,
+	})
+	defer conn.Close()
+	if err := conn.HandshakeContext(ctx); err != nil {
+		return nil, err
+	}
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return nil, fmt.Errorf("%s did not present a certificate", link.Host)
+	}
+
+	leaf := state.PeerCertificates[0]
+	cert := &Certificate{
+		Host:    link.Host,
+		Issuer:  leaf.Issuer.String(),
+		Names:   leaf.DNSNames,
+		Version: tls.VersionName(state.Version),
+		Expires: leaf.NotAfter,
+		Chain:   []Link{},
+		Matches: leaf.VerifyHostname(link.Hostname()) == nil,
+	}
+	intermediates := x509.NewCertPool()
+	valid := leaf.NotBefore
+	for _, c := range state.PeerCertificates {
+		if c.NotBefore.After(valid) {
+			valid = c.NotBefore
+		}
+		cert.Chain = append(cert.Chain, Link{
+			Subject: c.Subject.String(),
+			Issuer:  c.Issuer.String(),
+			Expires: c.NotAfter,
+		})
+		if c.NotAfter.Before(cert.Expires) {
+			cert.Expires = c.NotAfter
+		}
+		if c != leaf {
+			intermediates.AddCert(c)
+		}
+	}
+	// verify the chain at a time it is valid so expiry is reported on its own
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   valid.Add(time.Second),
+	})
+	cert.Trusted = err == nil
+	return cert, nil
+}
+
+// Returns the findings for the certificate. A certificate that expires within days
+// is reported as expiring. An untrusted certificate is only a notice if insecure is
+// true, since the certificates that are not trusted are accepted then.
+func (c *Certificate) Findings(link string, days int, insecure bool) []Finding {
+	findings := []Finding{}
+	now := time.Now()
+	switch {
+	case now.After(c.Expires):
+		findings = append(findings, NewFinding(
+			CERT_EXPIRED,
+			ERROR,
+			link,
+			fmt.Sprintf("the certificate for %s expired on %s", c.Host, c.Expires.Format(time.DateOnly)),
+		))
+	case now.AddDate(0, 0, days).After(c.Expires):
+		findings = append(findings, NewFinding(
+			CERT_EXPIRING,
+			WARNING,
+			link,
+			fmt.Sprintf("the certificate for %s expires on %s", c.Host, c.Expires.Format(time.DateOnly)),
+		))
+	}
+	if !c.Matches {
+		findings = append(findings, NewFinding(
+			CERT_MISMATCH,
+			ERROR,
+			link,
+			fmt.Sprintf("the certificate for %s is only valid for %v", c.Host, c.Names),
+		))
+	}
+	if !c.Trusted {
+		severity := ERROR
+		if insecure {
+			severity = NOTICE
+		}
+		findings = append(findings, NewFinding(
+			CERT_UNTRUSTED,
+			severity,
+			link,
+			fmt.Sprintf("the certificate for %s is issued by %s, which is not trusted", c.Host, c.Issuer),
+		))
+	}
+	return findings
+}
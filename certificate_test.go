@@ -0,0 +1,47 @@
+package linkt
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCertificateFindings(t *testing.T) {
+	valid := Certificate{Host: "example.com", Expires: time.Now().AddDate(1, 0, 0), Matches: true, Trusted: true}
+	with := func(change func(c *Certificate)) Certificate {
+		c := valid
+		change(&c)
+		return c
+	}
+	tests := []struct {
+		name     string
+		cert     Certificate
+		insecure bool
+		// kind and severity of each finding
+		want []string
+	}{
+		{"valid", valid, false, []string{}},
+		{"expired", with(func(c *Certificate) { c.Expires = time.Now().AddDate(0, 0, -1) }), false, []string{CERT_EXPIRED + " " + ERROR}},
+		{"expiring", with(func(c *Certificate) { c.Expires = time.Now().AddDate(0, 0, 10) }), false, []string{CERT_EXPIRING + " " + WARNING}},
+		{"mismatch", with(func(c *Certificate) { c.Matches = false }), false, []string{CERT_MISMATCH + " " + ERROR}},
+		{"untrusted", with(func(c *Certificate) { c.Trusted = false }), false, []string{CERT_UNTRUSTED + " " + ERROR}},
+		{"untrusted and insecure", with(func(c *Certificate) { c.Trusted = false }), true, []string{CERT_UNTRUSTED + " " + NOTICE}},
+		{
+			"expired, mismatched, and insecure",
+			with(func(c *Certificate) { c.Expires, c.Matches = time.Now().AddDate(0, 0, -1), false }),
+			true,
+			[]string{CERT_EXPIRED + " " + ERROR, CERT_MISMATCH + " " + ERROR},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, f := range tt.cert.Findings("https://example.com", 30, tt.insecure) {
+				got = append(got, f.Kind+" "+f.Severity)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Findings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package linkt
+
+import (
+	"fmt"
+)
+
+// Kind of finding reported by the status check
+const BROKEN_LINK = "broken-link"
+const AMBIGUOUS_LINK = "ambiguous-link"
+
+// Inspects each page the crawler tests and returns the problems it finds with it.
+// A finding without a kind, severity, or URL gets the name of the check, WARNING,
+// and the URL of the page.
+type Check interface {
+	// Returns the name of the check.
+	Name() string
+	// Returns the problems found with page. The document of the page is only
+	// available if it is an internal HTML page.
+	Check(page Page) []Finding
+}
+
+// A check that calls a function with each page.
+type checkFunc struct {
+	name string
+	fn   func(page Page) []Finding
+}
+
+// Returns a check named name that calls fn with each page.
+func NewCheck(name string, fn func(page Page) []Finding) Check {
+	return checkFunc{name: name, fn: fn}
+}
+
+func (c checkFunc) Name() string {
+	return c.name
+}
+
+func (c checkFunc) Check(page Page) []Finding {
+	return c.fn(page)
+}
+
+// Returns the checks that every crawler runs on the pages it tests.
+func builtinChecks(options *Options, scope *Scope) []Check {
+	return []Check{
+		statusCheck{scope: scope},
+		redirectCheck{max: options.MaxRedirects},
+	}
+}
+
+// Reports a link that could not be requested, returned an error status, or may
+// have been blocked as a bot. A status the scope allows is not reported.
+type statusCheck struct {
+	scope *Scope
+}
+
+func (statusCheck) Name() string {
+	return "status"
+}
+
+func (c statusCheck) Check(page Page) []Finding {
+	link := page.URL().String()
+	switch {
+	case page.err != nil:
+		return []Finding{NewFinding(BROKEN_LINK, ERROR, link, fmt.Sprintf("could not be requested: %s", page.err))}
+	case page.response == nil, c.scope.Allowed(page.response.StatusCode):
+		return nil
+	}
+	switch Classify(page.response) {
+	case BROKEN:
+		return []Finding{NewFinding(BROKEN_LINK, ERROR, link, fmt.Sprintf("returned %s", page.response.Status))}
+	case AMBIGUOUS:
+		return []Finding{NewFinding(
+			AMBIGUOUS_LINK,
+			WARNING,
+			link,
+			fmt.Sprintf("returned %s, possibly because it was blocked as a bot", page.response.Status),
+		)}
+	}
+	return nil
+}
+
+// Reports redirect loops, long redirect chains, and redirects that could be avoided.
+type redirectCheck struct {
+	max int
+}
+
+func (redirectCheck) Name() string {
+	return "redirect"
+}
+
+func (c redirectCheck) Check(page Page) []Finding {
+	if page.response == nil {
+		return nil
+	}
+	return Redirects(page, c.max)
+}
+
+// Runs each check on page and returns the findings, with the missing fields of
+// each finding filled in.
+func run(checks []Check, page Page) []Finding {
+	findings := []Finding{}
+	for _, c := range checks {
+		for _, f := range c.Check(page) {
+			if f.Kind == "" {
+				f.Kind = c.Name()
+			}
+			if f.Severity == "" {
+				f.Severity = WARNING
+			}
+			if f.URL == "" {
+				f.URL = page.URL().String()
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
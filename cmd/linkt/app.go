@@ -0,0 +1,611 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/barreirokevin/linkt"
+)
+
+// String for each command
+const SITEMAP = linkt.SITEMAP
+const TEST = linkt.TEST
+const SCREENSHOT = linkt.SCREENSHOT
+const SEO = linkt.SEO
+const A11Y = linkt.A11Y
+const PERF = linkt.PERF
+const DIFF = "test diff"
+const CONFIG = "config"
+const HELP = "help"
+
+// Exit code of linkt
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitUsage       = 2
+	exitInterrupted = 130
+)
+
+// The number of pages ranked in a performance report.
+const ranked = 10
+
+// Represents an instance of linkt.
+type App struct {
+	// command to run, nil if none was given
+	command *Command
+	// URLs to run the command on, and the one it is running on
+	urls    []string
+	url     string
+	options *Options
+	logger  *slog.Logger
+}
+
+// Runs the command of the app on each of its URLs and returns the exit code. A
+// crawl stops when ctx is done and its partial results are saved, and the URLs
+// after it are skipped.
+func (app *App) Run(ctx context.Context) int {
+	switch {
+	case app.options.version:
+		app.Version()
+		return exitOK
+	case app.command == nil:
+		fmt.Print((*Command)(nil).help())
+		return exitOK
+	case !app.command.crawls():
+		return app.command.run(app, ctx)
+	case len(app.urls) == 0:
+		return app.usage("missing URL")
+	}
+	// an expired rule is no longer applied, so its links may fail again
+	for _, i := range app.options.Ignore {
+		if !i.Expired(time.Now()) {
+			continue
+		}
+		reason := ""
+		if i.Reason != "" {
+			reason = ": " + i.Reason
+		}
+		fmt.Printf(
+			"\n%s[WARNING]%s the ignore rule for %s expired after %s%s\n",
+			Yellow, Reset, i.Pattern, i.Expires.Format(time.DateOnly), reason,
+		)
+	}
+	code := exitOK
+	for _, u := range app.urls {
+		app.url = u
+		switch c := app.command.run(app, ctx); c {
+		case exitOK:
+		case exitInterrupted, exitUsage:
+			return c
+		default:
+			code = c
+		}
+	}
+	return code
+}
+
+// Prints msg followed by the help for the command of the app to standard output,
+// and returns the exit code for a usage error.
+func (app *App) usage(msg string) int {
+	fmt.Printf("\n%s[ERROR]%s %s\n", Red, Reset, msg)
+	fmt.Print(app.command.help())
+	return exitUsage
+}
+
+// Executes the sitemap command for linkt.
+func (app *App) Sitemap(ctx context.Context) int {
+	switch {
+	case app.options.xml && app.options.Directory == "":
+		return app.usage("the xml option requires the dir option")
+	case !app.options.xml && !app.options.print:
+		return app.usage("the xml or print option is required")
+	}
+	root, err := app.root()
+	if err != nil {
+		app.logger.Error("missing or invalid URL", "url", app.url, "error", err)
+		return exitError
+	}
+	done := make(chan bool)
+	if !app.options.debug {
+		go app.Progress(done)
+	}
+	_, sitemap, err := app.crawl(ctx, root)
+	if !app.options.debug {
+		done <- err == nil
+	}
+	interrupted, failed := app.interrupted(err)
+	if failed {
+		return exitError
+	}
+	if app.options.print {
+		sitemap.Print()
+	}
+	if app.options.xml {
+		if err := sitemap.XML(app.directory()); err != nil {
+			app.logger.Error("error writing the sitemap", "error", err)
+			return exitError
+		}
+	}
+	if interrupted {
+		return exitInterrupted
+	}
+	return exitOK
+}
+
+// Tests a site for broken links, namely links that return a 4xx or 5xx HTTP error.
+func (app *App) Test(ctx context.Context) int {
+	if app.reporting() && app.options.Directory == "" {
+		return app.usage("the json, html, and junit options require the dir option")
+	}
+	root, err := app.root()
+	if err != nil {
+		app.logger.Error("missing or invalid URL", "url", app.url, "error", err)
+		return exitError
+	}
+	crawler, _, err := app.crawl(ctx, root)
+	interrupted, failed := app.interrupted(err)
+	if failed {
+		return exitError
+	}
+	name := app.reportName("")
+	if app.options.json {
+		if err := app.save(name, crawler.Records()); err != nil {
+			app.logger.Error("error saving the test results", "error", err)
+			return exitError
+		}
+	}
+	if err := app.report(name, crawler.Records()); err != nil {
+		app.logger.Error("error writing the report", "error", err)
+		return exitError
+	}
+	if interrupted {
+		return exitInterrupted
+	}
+	// only the errors that are not ignored or known fail the test
+	if PrintSuppressed(crawler.Records()) {
+		return exitError
+	}
+	return exitOK
+}
+
+// Compares the JSON results of two tests and prints the links that broke, were
+// fixed, changed status, got slower, or were added or removed. A link that broke or
+// got slower fails the comparison.
+func (app *App) Diff(ctx context.Context) int {
+	if len(app.urls) != 2 {
+		return app.usage("expected the JSON results of the old and the new test")
+	}
+	switch app.options.output {
+	case "text", "json", "markdown":
+	default:
+		return app.usage(fmt.Sprintf("unknown output %q, expected text, json, or markdown", app.options.output))
+	}
+	old, err := load(app.urls[0])
+	if err != nil {
+		app.logger.Error("error reading the old test results", "error", err)
+		return exitError
+	}
+	new, err := load(app.urls[1])
+	if err != nil {
+		app.logger.Error("error reading the new test results", "error", err)
+		return exitError
+	}
+	diff := linkt.NewDiff(old, new, app.options.slower)
+	switch app.options.output {
+	case "text":
+		PrintDiff(diff)
+	case "json":
+		data, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			app.logger.Error("error encoding the changes", "error", err)
+			return exitError
+		}
+		fmt.Println(string(data))
+	case "markdown":
+		fmt.Print(Markdown(diff))
+	}
+	if diff.Regressed() {
+		return exitError
+	}
+	return exitOK
+}
+
+// Audits each page of a site for SEO problems, such as missing or duplicate titles
+// and meta descriptions.
+func (app *App) Seo(ctx context.Context) int {
+	if app.reporting() && app.options.Directory == "" {
+		return app.usage("the json, html, and junit options require the dir option")
+	}
+	root, err := app.root()
+	if err != nil {
+		app.logger.Error("missing or invalid URL", "url", app.url, "error", err)
+		return exitError
+	}
+	crawler, _, err := app.crawl(ctx, root)
+	interrupted, failed := app.interrupted(err)
+	if failed {
+		return exitError
+	}
+	PrintDuplicates(crawler.Duplicates())
+	PrintClusters(crawler.Clusters())
+	name := app.reportName("seo-")
+	if app.options.json {
+		// save the findings for each page along with the duplicates
+		err := app.save(name, struct {
+			Pages      []linkt.Record    `json:"pages"`
+			Duplicates []linkt.Duplicate `json:"duplicates"`
+			Clusters   []linkt.Cluster   `json:"clusters"`
+		}{crawler.Records(), crawler.Duplicates(), crawler.Clusters()})
+		if err != nil {
+			app.logger.Error("error saving the SEO audit", "error", err)
+			return exitError
+		}
+	}
+	if err := app.report(name, crawler.Records()); err != nil {
+		app.logger.Error("error writing the report", "error", err)
+		return exitError
+	}
+	if interrupted {
+		return exitInterrupted
+	}
+	return exitOK
+}
+
+// Audits each page of a site for accessibility violations with axe-core.
+func (app *App) A11y(ctx context.Context) int {
+	if app.reporting() && app.options.Directory == "" {
+		return app.usage("the json, html, and junit options require the dir option")
+	}
+	root, err := app.root()
+	if err != nil {
+		app.logger.Error("missing or invalid URL", "url", app.url, "error", err)
+		return exitError
+	}
+	crawler, _, err := app.crawl(ctx, root)
+	interrupted, failed := app.interrupted(err)
+	if failed {
+		return exitError
+	}
+	name := app.reportName("a11y-")
+	if app.options.json {
+		if err := app.save(name, crawler.Records()); err != nil {
+			app.logger.Error("error saving the accessibility audit", "error", err)
+			return exitError
+		}
+	}
+	if err := app.report(name, crawler.Records()); err != nil {
+		app.logger.Error("error writing the report", "error", err)
+		return exitError
+	}
+	if interrupted {
+		return exitInterrupted
+	}
+	return exitOK
+}
+
+// Measures the timing, size, and weight of each page of a site, ranks the slowest
+// and heaviest pages, and reports the pages that are over a budget.
+func (app *App) Perf(ctx context.Context) int {
+	if app.reporting() && app.options.Directory == "" {
+		return app.usage("the json, html, and junit options require the dir option")
+	}
+	root, err := app.root()
+	if err != nil {
+		app.logger.Error("missing or invalid URL", "url", app.url, "error", err)
+		return exitError
+	}
+	crawler, _, err := app.crawl(ctx, root)
+	interrupted, failed := app.interrupted(err)
+	if failed {
+		return exitError
+	}
+	pages := crawler.Performance()
+	PrintPerformance(pages, ranked)
+	name := app.reportName("perf-")
+	if app.options.json {
+		err := app.save(name, struct {
+			Pages       []linkt.Record  `json:"pages"`
+			Performance []linkt.Metrics `json:"performance"`
+		}{crawler.Records(), pages})
+		if err != nil {
+			app.logger.Error("error saving the performance report", "error", err)
+			return exitError
+		}
+	}
+	// report the pages over a budget along with the other findings
+	budgets := map[string][]linkt.Finding{}
+	for _, m := range pages {
+		budgets[m.URL] = m.Findings
+	}
+	records := []linkt.Record{}
+	for _, r := range crawler.Records() {
+		r.Findings = append(append([]linkt.Finding{}, r.Findings...), budgets[r.URL]...)
+		records = append(records, r)
+	}
+	if err := app.report(name, records); err != nil {
+		app.logger.Error("error writing the report", "error", err)
+		return exitError
+	}
+	if interrupted {
+		return exitInterrupted
+	}
+	return exitOK
+}
+
+// Takes screenshot of each page in a site and saves them to a directory.
+func (app *App) Screenshot(ctx context.Context) int {
+	if app.options.Directory == "" {
+		return app.usage("the dir option is required")
+	}
+	// create directory to store screenshots
+	if err := os.MkdirAll(app.directory(), os.ModePerm); err != nil {
+		app.logger.Error("directory not found", "error", err)
+		return exitError
+	}
+	crawler, err := app.crawler()
+	if err != nil {
+		app.logger.Error("invalid options", "error", err)
+		return exitError
+	}
+	root, err := app.root()
+	if err != nil {
+		app.logger.Error("missing or invalid URL", "url", app.url, "error", err)
+		return exitError
+	}
+	done := make(chan bool)
+	if !app.options.debug {
+		go app.Progress(done)
+	}
+	_, err = crawler.Crawl(ctx, root)
+	if !app.options.debug {
+		done <- err == nil
+	}
+	interrupted, failed := app.interrupted(err)
+	if failed {
+		return exitError
+	}
+	if err := crawler.Manifest().Write(app.directory()); err != nil {
+		app.logger.Error("error writing the screenshot manifest", "error", err)
+		return exitError
+	}
+	if interrupted {
+		return exitInterrupted
+	}
+	if app.options.Baseline != "" {
+		if PrintComparisons(crawler.Comparisons(), app.options.Threshold) {
+			return exitError
+		}
+	}
+	return exitOK
+}
+
+// Returns the URL the app is running on as the root of a site.
+func (app *App) root() (*url.URL, error) {
+	root, err := url.Parse(strings.TrimSuffix(app.url, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if root.Scheme == "" || root.Host == "" {
+		return nil, errors.New("the URL must have a scheme and host")
+	}
+	return root, nil
+}
+
+// Returns the directory the results for the URL the app is running on are saved
+// to. The results for each URL are kept apart when the app runs on several.
+func (app *App) directory() string {
+	if len(app.urls) > 1 {
+		return filepath.Join(app.options.Directory, app.reportName(""))
+	}
+	return app.options.Directory
+}
+
+// Saves v as JSON to the file named name in the directory specified.
+func (app *App) save(name string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(app.options.Directory, os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(app.options.Directory, name+".json"), data, 0666)
+}
+
+// Returns a new crawler that performs the command of the app with its options and
+// prints its work.
+func (app *App) crawler() (*linkt.Crawler, error) {
+	options := app.options.Options
+	options.Action = app.command.Name
+	options.Logger = app.logger
+	options.Delay = time.Duration(app.options.delay) * time.Millisecond
+	if app.options.known != "" {
+		known, err := load(app.options.known)
+		if err != nil {
+			return nil, fmt.Errorf("invalid baseline: %w", err)
+		}
+		options.Known = known
+	}
+	if app.command.Name == SCREENSHOT {
+		options.Directory = app.directory()
+	}
+	crawler, err := linkt.NewCrawler(&options, printer{})
+	if err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+	return crawler, nil
+}
+
+// Returns the records in the JSON results of a previous test at path.
+func load(path string) ([]linkt.Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	records := []linkt.Record{}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("error reading the test results in %s: %w", path, err)
+	}
+	return records, nil
+}
+
+// Crawls the site at root with a new crawler and returns the crawler and the
+// sitemap it built.
+func (app *App) crawl(ctx context.Context, root *url.URL) (*linkt.Crawler, *linkt.Sitemap, error) {
+	crawler, err := app.crawler()
+	if err != nil {
+		return nil, nil, err
+	}
+	sitemap, err := crawler.Crawl(ctx, root)
+	return crawler, sitemap, err
+}
+
+// Returns whether err is the result of the crawl being interrupted, in which case
+// the partial results should be saved, and whether the crawl failed for any other
+// error, which is logged.
+func (app *App) interrupted(err error) (interrupted bool, failed bool) {
+	switch {
+	case err == nil:
+		return false, false
+	case errors.Is(err, context.Canceled):
+		fmt.Printf("\n%s[INTERRUPTED]%s the crawl was stopped, saving partial results\n", Yellow, Reset)
+		return true, false
+	default:
+		app.logger.Error("error crawling the site", "error", err)
+		return false, true
+	}
+}
+
+// Validates the config file at the path after the validate argument, or the one
+// linkt reads its options from, and prints its problems to standard output.
+func (app *App) Config(ctx context.Context) int {
+	if len(app.urls) == 0 || app.urls[0] != "validate" || len(app.urls) > 2 {
+		return app.usage("expected validate and the optional path of a config file")
+	}
+	path := app.options.config
+	if len(app.urls) == 2 {
+		path = app.urls[1]
+	}
+	if path == "" {
+		path = os.Getenv(envPrefix + "CONFIG")
+	}
+	config, err := LoadConfig(path)
+	if err == nil && config.path == "" {
+		err = fmt.Errorf("no config file, expected one of %s", strings.Join(configFiles, ", "))
+	}
+	if err != nil {
+		fmt.Printf("\n%s[ERROR]%s %s\n\n", Red, Reset, err)
+		return exitError
+	}
+	if err := config.Validate(); err != nil {
+		fmt.Printf("\n%s[INVALID]%s %s\n", Red, Reset, config.path)
+		for _, e := range unwrap(err) {
+			fmt.Printf("\t%s\n", e)
+		}
+		fmt.Println()
+		return exitError
+	}
+	fmt.Printf("\n%s[VALID]%s %s\n\n", Green, Reset, config.path)
+	return exitOK
+}
+
+// Prints the help for the command named by the first argument, or the list of
+// commands, to standard output.
+func (app *App) Help(ctx context.Context) int {
+	if len(app.urls) == 0 {
+		fmt.Print((*Command)(nil).help())
+		return exitOK
+	}
+	name := strings.Join(app.urls, " ")
+	c := lookup(strings.ToLower(name))
+	if c == nil {
+		fmt.Printf("\n%s[ERROR]%s unknown command %q\n", Red, Reset, name)
+		fmt.Print((*Command)(nil).help())
+		return exitUsage
+	}
+	fmt.Print(c.help())
+	return exitOK
+}
+
+// Prints the linkt version and logo to standard output.
+func (app *App) Version() {
+	version := `
+		
+ ___       ___  ________   ___  __    _________
+|\  \     |\  \|\   ___  \|\  \|\  \ |\___   ___\
+\ \  \    \ \  \ \  \\ \  \ \  \/  /|\|___ \  \_|
+ \ \  \    \ \  \ \  \\ \  \ \   ___  \   \ \  \
+  \ \  \____\ \  \ \  \\ \  \ \  \\ \  \   \ \  \
+   \ \_______\ \__\ \__\\ \__\ \__\\ \__\   \ \__\
+    \|_______|\|__|\|__| \|__|\|__| \|__|    \|__|    v0.0.1, built with Go 1.23.2
+                                                                     
+
+		`
+	fmt.Printf("%s\n", version)
+}
+
+// Prints text to stdout in such a manner that it gives the impression the text is moving
+// while the spider is working. It stops when done receives a value, which is true if
+// the crawl succeeded.
+func (app *App) Progress(done chan bool) {
+	for {
+		switch app.command.Name {
+		case SITEMAP:
+			select {
+			case success := <-done:
+				if !success {
+					fmt.Println()
+					return
+				}
+				fmt.Printf(
+					"\n%s[SUCCESS]%s sitemap was created!\n",
+					Green, Reset)
+				if app.options.xml {
+					fmt.Printf(
+						"\nsitemap was saved to %s%s/sitemap.xml%s\n\n",
+						Green, app.directory(), Reset)
+				}
+				return
+			default:
+				dots := []string{".  ", ".. ", "...", " ..", "  .", "   "}
+				for _, s := range dots {
+					fmt.Printf(
+						"\r%s[PENDING]%s collecting links%s%s%s",
+						Orange, Reset, Orange, s, Reset)
+					time.Sleep((1 * time.Second) / 4)
+				}
+			}
+		case SCREENSHOT:
+			select {
+			case success := <-done:
+				if !success {
+					fmt.Println()
+					return
+				}
+				fmt.Printf(
+					"\n%s[SUCCESS]%s screenshots were taken!\n",
+					Green, Reset)
+				fmt.Printf(
+					"\nscreenshots were saved to %s%s%s\n\n",
+					Green, app.directory(), Reset)
+				return
+			default:
+				dots := []string{".  ", ".. ", "...", " ..", "  .", "   "}
+				for _, s := range dots {
+					fmt.Printf(
+						"\r%s[PENDING]%s taking screenshots%s%s%s",
+						Orange, Reset, Orange, s, Reset)
+					time.Sleep((1 * time.Second) / 4)
+				}
+			}
+		}
+	}
+}
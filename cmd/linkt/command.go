@@ -0,0 +1,371 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// A command of linkt, with the options it accepts.
+type Command struct {
+	Name    string
+	Summary string
+	// arguments after the options, shown in the help
+	Args string
+	// options accepted by the command, besides the global ones
+	groups []group
+	run    func(app *App, ctx context.Context) int
+}
+
+// Returns the commands of linkt in the order they are listed in the help.
+func commands() []*Command {
+	return []*Command{
+		{
+			Name:    SITEMAP,
+			Summary: "Build a sitemap with URL as the root.",
+			Args:    "<url>...",
+			groups:  []group{sitemapFlags, crawlFlags},
+			run:     (*App).Sitemap,
+		},
+		{
+			Name:    TEST,
+			Summary: "Test for broken links in anchor, image, link, and script tags.",
+			Args:    "<url>...",
+			groups:  []group{reportFlags, testFlags, crawlFlags},
+			run:     (*App).Test,
+		},
+		{
+			Name:    DIFF,
+			Summary: "Compare the JSON results of two tests and report what changed.",
+			Args:    "<old> <new>",
+			groups:  []group{diffFlags},
+			run:     (*App).Diff,
+		},
+		{
+			Name:    SCREENSHOT,
+			Summary: "Take screenshots of all the pages on a site.",
+			Args:    "<url>...",
+			groups:  []group{screenshotFlags, crawlFlags},
+			run:     (*App).Screenshot,
+		},
+		{
+			Name:    SEO,
+			Summary: "Audit the pages on a site for SEO problems.",
+			Args:    "<url>...",
+			groups:  []group{reportFlags, seoFlags, crawlFlags},
+			run:     (*App).Seo,
+		},
+		{
+			Name:    A11Y,
+			Summary: "Audit the pages on a site for accessibility violations.",
+			Args:    "<url>...",
+			groups:  []group{reportFlags, a11yFlags, crawlFlags},
+			run:     (*App).A11y,
+		},
+		{
+			Name:    PERF,
+			Summary: "Measure the performance of the pages on a site.",
+			Args:    "<url>...",
+			groups:  []group{reportFlags, perfFlags, crawlFlags},
+			run:     (*App).Perf,
+		},
+		{
+			Name:    CONFIG,
+			Summary: "Validate the config file.",
+			Args:    "validate [<path>]",
+			run:     (*App).Config,
+		},
+		{
+			Name:    HELP,
+			Summary: "Display help for a command.",
+			Args:    "<command>",
+			run:     (*App).Help,
+		},
+	}
+}
+
+// Returns the command named name, or nil if there is none. The name of a
+// subcommand is the name of its command followed by its own, e.g. test diff.
+func lookup(name string) *Command {
+	for _, c := range commands() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// Returns true if the command crawls the sites at its URLs.
+func (c *Command) crawls() bool {
+	return c != nil && c.Name != HELP && c.Name != CONFIG && c.Name != DIFF
+}
+
+// Returns a flag set with the options of the command, which set the values in
+// options. A nil command only has the global options.
+func (c *Command) flags(options *Options) *flag.FlagSet {
+	groups := []group{globalFlags}
+	if c != nil {
+		groups = append(groups, c.groups...)
+	}
+	return flags(groups, options)
+}
+
+// Returns a flag set with the options in groups. An option in more than one group
+// is defined by the first.
+func flags(groups []group, options *Options) *flag.FlagSet {
+	fs := flag.NewFlagSet("linkt", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	for _, g := range groups {
+		scratch := flag.NewFlagSet("", flag.ContinueOnError)
+		g(scratch, options)
+		scratch.VisitAll(func(f *flag.Flag) {
+			if fs.Lookup(f.Name) == nil {
+				fs.Var(f.Value, f.Name, f.Usage)
+			}
+		})
+	}
+	return fs
+}
+
+// Parses args with the flag set fs, allowing options before, between, and after the
+// arguments, and returns the arguments and their indexes in args. Everything after
+// -- is an argument.
+func parse(fs *flag.FlagSet, args []string) ([]string, []int, error) {
+	end := len(args)
+	for i, a := range args {
+		if a == "--" {
+			end = i
+			break
+		}
+	}
+	positional := []string{}
+	indexes := []int{}
+	remaining := args[:end]
+	for {
+		if err := fs.Parse(remaining); err != nil {
+			return nil, nil, err
+		}
+		remaining = fs.Args()
+		if len(remaining) == 0 {
+			break
+		}
+		positional = append(positional, remaining[0])
+		indexes = append(indexes, end-len(remaining))
+		remaining = remaining[1:]
+	}
+	for i := end + 1; i < len(args); i++ {
+		positional = append(positional, args[i])
+		indexes = append(indexes, i)
+	}
+	return positional, indexes, nil
+}
+
+// Returns the URLs to crawl, which are the arguments and the lines of the file
+// specified with the urls option. An argument or file of - is read from stdin.
+// Blank lines and lines starting with # are skipped.
+func urls(args []string, file string, stdin io.Reader) ([]string, error) {
+	found := []string{}
+	read := func(r io.Reader) error {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line != "" && !strings.HasPrefix(line, "#") {
+				found = append(found, line)
+			}
+		}
+		return scanner.Err()
+	}
+	for _, a := range args {
+		if a == "-" {
+			if err := read(stdin); err != nil {
+				return nil, fmt.Errorf("error reading URLs from standard input: %w", err)
+			}
+			continue
+		}
+		found = append(found, strings.TrimSpace(a))
+	}
+	switch file {
+	case "":
+	case "-":
+		if err := read(stdin); err != nil {
+			return nil, fmt.Errorf("error reading URLs from standard input: %w", err)
+		}
+	default:
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		if err := read(f); err != nil {
+			return nil, fmt.Errorf("error reading URLs from %s: %w", file, err)
+		}
+	}
+	return found, nil
+}
+
+// Returns the help for the command, generated from its options. The help of a nil
+// command lists the commands.
+func (c *Command) help() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 8, 2, ' ', 0)
+	if c == nil || c.Name == HELP {
+		fmt.Fprintf(&b, "\nUsage: linkt <command> [options] [<args>]\n\nCommands:\n")
+		for _, c := range commands() {
+			name := c.Name
+			if !c.crawls() {
+				name += " " + c.Args
+			}
+			fmt.Fprintf(w, "\t%s\t%s\n", name, c.Summary)
+		}
+		w.Flush()
+		fmt.Fprintf(&b, "\nOptions:\n")
+		usage(w, globalFlags)
+		w.Flush()
+		fmt.Fprintf(&b, "\nRun linkt help <command> for the options of a command.\n\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "\nUsage: linkt %s [options] %s\n\n%s\n\nOptions:\n", c.Name, c.Args, c.Summary)
+	for _, g := range c.groups {
+		usage(w, g)
+	}
+	usage(w, globalFlags)
+	w.Flush()
+	b.WriteString("\n")
+	return b.String()
+}
+
+// Writes a line to w for each option in the group, sorted by name. An alias is
+// shown with the option it stands for, and an option without a description is
+// hidden.
+func usage(w io.Writer, g group) {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	g(fs, NewOptions())
+	aliases := map[string][]string{}
+	fs.VisitAll(func(f *flag.Flag) {
+		if f.Usage == "" {
+			fs.VisitAll(func(o *flag.Flag) {
+				if o != f && o.Usage != "" && o.Value == f.Value {
+					aliases[o.Name] = append(aliases[o.Name], "-"+f.Name)
+				}
+			})
+		}
+	})
+	fs.VisitAll(func(f *flag.Flag) {
+		if f.Usage == "" {
+			return
+		}
+		arg, text := flag.UnquoteUsage(f)
+		if arg != "" {
+			arg = " " + arg
+		}
+		names := append(aliases[f.Name], "--"+f.Name)
+		switch f.DefValue {
+		case "", "0", "false", "0s":
+		default:
+			text += fmt.Sprintf(" Defaults to %s.", f.DefValue)
+		}
+		fmt.Fprintf(w, "\t%s%s\t%s\n", strings.Join(names, ", "), arg, text)
+	})
+}
+
+// Returns an app that runs the command in args, the arguments linkt was executed
+// with, on the URLs in args and stdin. The command is the first argument, and its
+// options may come before or after it. The options are read from the config file
+// first, then from the environment, and then from args, and the URLs in the config
+// file are used if there are none in args.
+func NewApp(args []string, stdin io.Reader) (*App, error) {
+	options := NewOptions()
+	app := &App{options: options, logger: NewLogger(false)}
+
+	// find the command with the options of every command, which are discarded
+	all := []group{globalFlags}
+	for _, c := range commands() {
+		all = append(all, c.groups...)
+	}
+	found := NewOptions()
+	positional, indexes, err := parse(flags(all, found), args)
+	if isHelp(err) {
+		// show the help of the command the option was given to
+		for i, a := range args {
+			if c := lookup(strings.ToLower(a)); c != nil {
+				app.command = c
+				if i+1 < len(args) {
+					if sub := lookup(c.Name + " " + strings.ToLower(args[i+1])); sub != nil {
+						app.command = sub
+					}
+				}
+				break
+			}
+		}
+		return app, err
+	}
+	if err != nil {
+		return app, err
+	}
+	if len(positional) > 0 {
+		name := strings.ToLower(positional[0])
+		app.command = lookup(name)
+		if app.command == nil {
+			return app, fmt.Errorf("unknown command %q", positional[0])
+		}
+		remove := []int{indexes[0]}
+		if len(positional) > 1 {
+			if sub := lookup(name + " " + strings.ToLower(positional[1])); sub != nil {
+				app.command = sub
+				remove = append(remove, indexes[1])
+			}
+		}
+		// remove the command from the arguments, from the last index so the others
+		// do not move
+		args = append([]string{}, args...)
+		for i := len(remove) - 1; i >= 0; i-- {
+			args = append(args[:remove[i]], args[remove[i]+1:]...)
+		}
+	}
+
+	// read the options of the command from the config file and the environment
+	fs := app.command.flags(options)
+	var config *Config
+	if app.command.crawls() {
+		path := found.config
+		if path == "" {
+			path = os.Getenv(envPrefix + "CONFIG")
+		}
+		if config, err = LoadConfig(path); err != nil {
+			return app, err
+		}
+		if err := config.Validate(); err != nil {
+			return app, fmt.Errorf("invalid config %s:\n%w", config.path, err)
+		}
+		if err := config.Apply(app.command, fs); err != nil {
+			return app, fmt.Errorf("invalid config %s:\n%w", config.path, err)
+		}
+		if err := environment(fs); err != nil {
+			return app, fmt.Errorf("invalid environment variable: %w", err)
+		}
+	}
+
+	// parse the options of the command
+	positional, _, err = parse(fs, args)
+	app.logger = NewLogger(options.debug)
+	if err != nil || !app.command.crawls() {
+		app.urls = positional
+		return app, err
+	}
+	app.urls, err = urls(positional, options.urls, stdin)
+	if err == nil && len(app.urls) == 0 {
+		app.urls, err = config.URLs(app.command)
+	}
+	return app, err
+}
+
+// Returns true if err is the result of asking for help with the h or help option.
+func isHelp(err error) bool {
+	return errors.Is(err, flag.ErrHelp)
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/barreirokevin/linkt"
+	"gopkg.in/yaml.v3"
+)
+
+// Names of the files a config is read from when none is specified, in the order
+// they are looked for in the working directory.
+var configFiles = []string{"linkt.yaml", "linkt.yml", "linkt.toml"}
+
+// Prefix of the environment variables that set options.
+const envPrefix = "LINKT_"
+
+// Keys of a config that are not options.
+const (
+	// root URLs to run a command on when none are given
+	urlsKey = "urls"
+	// reports to save: json, html, or junit
+	reportersKey = "reporters"
+)
+
+// Options read from a config file, keyed by the name of the option as it is given
+// on the command line. The options of a single command are in a table named after
+// the command, and override the options for every command.
+type Config struct {
+	// file the config was read from, empty if there is none
+	path   string
+	values map[string]any
+}
+
+// Reads the config file at path, or the first config file in the working directory
+// if path is empty. The config is empty if path is empty and there is no config
+// file in the working directory.
+func LoadConfig(path string) (*Config, error) {
+	if path == "" {
+		for _, name := range configFiles {
+			if _, err := os.Stat(name); err == nil {
+				path = name
+				break
+			}
+		}
+		if path == "" {
+			return &Config{values: map[string]any{}}, nil
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the config: %w", err)
+	}
+	values := map[string]any{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		_, err = toml.Decode(string(data), &values)
+	default:
+		return nil, fmt.Errorf("unsupported config %s, expected a .yaml, .yml, or .toml file", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return &Config{path: path, values: keys(values)}, nil
+}
+
+// Returns the problems with the config, namely the keys that are not an option of
+// any command and the values an option does not accept.
+func (c *Config) Validate() error {
+	errs := []error{}
+	seen := map[string]bool{}
+	add := func(err error) {
+		if err != nil && !seen[err.Error()] {
+			seen[err.Error()] = true
+			errs = append(errs, err)
+		}
+	}
+
+	// options of no command
+	all := []group{globalFlags}
+	for _, cmd := range commands() {
+		all = append(all, cmd.groups...)
+	}
+	known := flags(all, NewOptions())
+	for _, k := range sorted(c.values) {
+		if k != urlsKey && k != reportersKey && lookup(k) == nil && known.Lookup(k) == nil {
+			add(fmt.Errorf("%s: not an option", k))
+		}
+	}
+
+	// values of the options of each command
+	for _, cmd := range commands() {
+		if !cmd.crawls() {
+			if _, ok := c.values[cmd.Name]; ok {
+				add(fmt.Errorf("%s: the %s command has no options to configure", cmd.Name, cmd.Name))
+			}
+			continue
+		}
+		options := NewOptions()
+		for _, err := range unwrap(c.Apply(cmd, cmd.flags(options))) {
+			add(err)
+		}
+		links, err := c.URLs(cmd)
+		add(err)
+		for _, link := range links {
+			if u, err := url.Parse(link); err != nil || u.Scheme == "" || u.Host == "" {
+				add(fmt.Errorf("%s: %q is not a URL with a scheme and host", urlsKey, link))
+			}
+		}
+		if _, err := linkt.NewScope(&options.Options); err != nil {
+			add(err)
+		}
+		if _, err := linkt.NewHeaders(&options.Options); err != nil {
+			add(err)
+		}
+		if cmd.Name == PERF {
+			if _, err := linkt.NewBudgets(options.Budgets); err != nil {
+				add(err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Sets the options of the command in fs to the values in the config, the values for
+// every command first and then the values in the table of the command. An option
+// the command does not accept is skipped, unless it is in the table of the command.
+func (c *Config) Apply(command *Command, fs *flag.FlagSet) error {
+	errs := []error{}
+	apply := func(values map[string]any, prefix string, strict bool) {
+		for _, k := range sorted(values) {
+			v := values[k]
+			switch {
+			case k == urlsKey:
+			case k == reportersKey && !strict && fs.Lookup("json") == nil:
+				// the command does not save reports
+			case k == reportersKey:
+				if err := reporters(fs, prefix+k, v); err != nil {
+					errs = append(errs, err)
+				}
+			case !strict && lookup(k) != nil:
+				// the table of a command
+			case fs.Lookup(k) == nil:
+				if strict {
+					errs = append(errs, fmt.Errorf("%s%s: not an option of the %s command", prefix, k, command.Name))
+				}
+			default:
+				if err := set(fs, k, v); err != nil {
+					errs = append(errs, fmt.Errorf("%s%w", prefix, err))
+				}
+			}
+		}
+	}
+	apply(c.values, "", false)
+	if v, ok := c.values[command.Name]; ok {
+		table, ok := v.(map[string]any)
+		if !ok {
+			return errors.Join(append(errs, fmt.Errorf("%s: expected a table of options", command.Name))...)
+		}
+		apply(keys(table), command.Name+".", true)
+	}
+	return errors.Join(errs...)
+}
+
+// Returns the URLs to run the command on when none are given, which are in the
+// table of the command or else for every command.
+func (c *Config) URLs(command *Command) ([]string, error) {
+	v, ok := c.values[urlsKey]
+	name := urlsKey
+	if table, isTable := c.values[command.Name].(map[string]any); isTable {
+		if tv, found := keys(table)[urlsKey]; found {
+			v, ok, name = tv, true, command.Name+"."+urlsKey
+		}
+	}
+	if !ok {
+		return []string{}, nil
+	}
+	values, isList := v.([]any)
+	if !isList {
+		values = []any{v}
+	}
+	links := []string{}
+	for _, l := range values {
+		s, isString := l.(string)
+		if !isString {
+			return nil, fmt.Errorf("%s: expected a list of URLs", name)
+		}
+		links = append(links, strings.TrimSpace(s))
+	}
+	return links, nil
+}
+
+// Sets each option in fs that has an environment variable, named LINKT_ followed by
+// the name of the option in uppercase with dashes replaced by underscores, e.g.
+// LINKT_USER_AGENT. An option that can be repeated gets a single value.
+func environment(fs *flag.FlagSet) error {
+	errs := []error{}
+	fs.VisitAll(func(f *flag.Flag) {
+		if f.Usage == "" { // an alias
+			return
+		}
+		name := envPrefix + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+		if v, ok := os.LookupEnv(name); ok {
+			if err := f.Value.Set(v); err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid value %q: %w", name, v, err))
+			}
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// Sets the option name in fs to value, or to each value if it is a list and the
+// option can be repeated. A table is only accepted by a tabular option.
+func set(fs *flag.FlagSet, name string, value any) error {
+	f := fs.Lookup(name)
+	values, isList := value.([]any)
+	if isList && !repeated(f.Value) {
+		return fmt.Errorf("%s: expected a single value", name)
+	} else if !isList {
+		values = []any{value}
+	}
+	for _, v := range values {
+		if table, isTable := v.(map[string]any); isTable {
+			if t, ok := f.Value.(tabular); ok {
+				if err := t.SetTable(keys(table)); err != nil {
+					return fmt.Errorf("%s: %w", name, err)
+				}
+				continue
+			}
+		}
+		s, err := scalar(v)
+		if err == nil {
+			err = f.Value.Set(s)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: invalid value %v: %w", name, v, err)
+		}
+	}
+	return nil
+}
+
+// Returns true if the option with value v can be repeated.
+func repeated(v flag.Value) bool {
+	switch v.(type) {
+	case *List, *Ignores:
+		return true
+	}
+	return false
+}
+
+// An option that can also be set to a table in a config file.
+type tabular interface {
+	SetTable(table map[string]any) error
+}
+
+// Sets the option of each reporter named in value, which is a report the command
+// saves, e.g. json.
+func reporters(fs *flag.FlagSet, name string, value any) error {
+	values, isList := value.([]any)
+	if !isList {
+		values = []any{value}
+	}
+	for _, v := range values {
+		s, isString := v.(string)
+		switch s = strings.ToLower(s); {
+		case !isString || (s != "json" && s != "html" && s != "junit"):
+			return fmt.Errorf("%s: invalid reporter %v, expected json, html, or junit", name, v)
+		case fs.Lookup(s) == nil:
+			return fmt.Errorf("%s: the command does not save reports", name)
+		}
+		fs.Set(s, "true")
+	}
+	return nil
+}
+
+// Returns the value of an option as it is given on the command line.
+func scalar(v any) (string, error) {
+	switch v := v.(type) {
+	case map[string]any, []any, nil:
+		return "", errors.New("expected a value")
+	case time.Time:
+		return v.Format(time.RFC3339), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+// Returns values with its keys in the form of an option name, in lowercase with
+// dashes instead of underscores.
+func keys(values map[string]any) map[string]any {
+	normal := map[string]any{}
+	for k, v := range values {
+		normal[strings.ReplaceAll(strings.ToLower(k), "_", "-")] = v
+	}
+	return normal
+}
+
+// Returns the keys of values in order.
+func sorted(values map[string]any) []string {
+	names := []string{}
+	for k := range values {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Returns the errors joined in err.
+func unwrap(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	if err != nil {
+		return []error{err}
+	}
+	return nil
+}
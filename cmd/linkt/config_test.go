@@ -0,0 +1,121 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Returns the config read from a YAML file with the content given.
+func config(t *testing.T, content string) *Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "linkt.yaml")
+	if err := os.WriteFile(path, []byte(content), 0666); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestConfigApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		content string
+		// error the config is expected to have, empty if none
+		err   string
+		check func(o *Options) bool
+	}{
+		{"option", TEST, "rate: 4", "", func(o *Options) bool { return o.Rate == 4 }},
+		{"table of the command", TEST, "rate: 4\ntest:\n  rate: 2", "", func(o *Options) bool { return o.Rate == 2 }},
+		{"table of another command", TEST, "rate: 4\nperf:\n  rate: 2", "", func(o *Options) bool { return o.Rate == 4 }},
+		{"option of another command", SITEMAP, "viewport: 1280x800", "", func(o *Options) bool { return len(o.Viewports) == 0 }},
+		{"option of another command in its table", SITEMAP, "sitemap:\n  viewport: 1280x800", "sitemap.viewport: not an option of the sitemap command", nil},
+		{"underscores", TEST, "user_agent: bot", "", func(o *Options) bool { return o.UserAgent == "bot" }},
+		{"repeated", TEST, "exclude: [/a/, /b/]", "", func(o *Options) bool { return len(o.Exclude) == 2 }},
+		{"list of a single value", TEST, "rate: [1, 2]", "rate: expected a single value", nil},
+		{"invalid value", TEST, "test:\n  rate: fast", "test.rate: invalid value fast", nil},
+		{"not a table", TEST, "test: 3", "test: expected a table of options", nil},
+		{"reporters", TEST, "reporters: [json, junit]", "", func(o *Options) bool { return o.json && o.junit && !o.html }},
+		{"reporters of a command without reports", SITEMAP, "reporters: [json]", "", func(o *Options) bool { return !o.json }},
+		{"invalid reporter", TEST, "reporters: [pdf]", "reporters: invalid reporter pdf", nil},
+		{
+			"ignore rule",
+			TEST,
+			"ignore:\n  - ^https://a\\.example\\.com/\n  - pattern: ^https://b\\.example\\.com/\n    reason: blocks bots\n    expires: 2027-06-30",
+			"",
+			func(o *Options) bool {
+				return len(o.Ignore) == 2 && o.Ignore[0].Reason == "" && o.Ignore[1].Reason == "blocks bots" &&
+					o.Ignore[1].Expires.Format(time.DateOnly) == "2027-06-30"
+			},
+		},
+		{"invalid ignore rule", TEST, "ignore:\n  - pattern: x\n    until: 2027-06-30", "unknown key \"until\"", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command := lookup(tt.command)
+			options := NewOptions()
+			err := config(t, tt.content).Apply(command, command.flags(options))
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatalf("Apply() = %v, want no error", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Fatalf("Apply() = %v, want an error with %q", err, tt.err)
+			case tt.check != nil && !tt.check(options):
+				t.Errorf("Apply() set the options to %+v", options)
+			}
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		// errors the config is expected to have
+		errs []string
+	}{
+		{"empty", "", nil},
+		{
+			"valid",
+			"urls: [https://example.com]\nexclude: [/blog/]\nreporters: [json]\ndir: results\n" +
+				"test:\n  ignore-status: [429, 5xx]\nscreenshot:\n  dir: screenshots\n  viewport: [1280x800]",
+			nil,
+		},
+		{"not an option", "colour: red", []string{"colour: not an option"}},
+		{"invalid value", "rate: fast", []string{"rate: invalid value fast"}},
+		{"invalid url", "urls: [example.com]", []string{`urls: "example.com" is not a URL with a scheme and host`}},
+		{"invalid url of a command", "test:\n  urls: 3", []string{"test.urls: expected a list of URLs"}},
+		{"command without options", "help:\n  debug: true", []string{"help: the help command has no options to configure"}},
+		{"invalid pattern", "exclude: [\"(\"]", []string{"invalid exclude pattern"}},
+		{"invalid status", "ignore-status: [600x]", []string{"invalid status"}},
+		{"several problems", "colour: red\ntest:\n  rate: fast", []string{"colour: not an option", "test.rate: invalid value fast"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := config(t, tt.content).Validate()
+			if len(tt.errs) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want no error", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() = nil, want errors with %q", tt.errs)
+			}
+			if got := len(unwrap(err)); got != len(tt.errs) {
+				t.Errorf("Validate() returned %d errors, want %d: %v", got, len(tt.errs), err)
+			}
+			for _, e := range tt.errs {
+				if !strings.Contains(err.Error(), e) {
+					t.Errorf("Validate() = %v, want an error with %q", err, e)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	// cancel the crawl on the first interrupt, and exit on the second
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	app, err := NewApp(os.Args[1:], os.Stdin)
+	switch {
+	case isHelp(err):
+		fmt.Print(app.command.help())
+		os.Exit(exitOK)
+	case err != nil:
+		fmt.Printf("\n%s[ERROR]%s %s\n", Red, Reset, err)
+		fmt.Print(app.command.help())
+		os.Exit(exitUsage)
+	}
+	os.Exit(app.Run(ctx))
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/barreirokevin/linkt"
+)
+
+// The values for the options available when executing linkt. The options that
+// configure the crawler are passed on to it.
+type Options struct {
+	linkt.Options
+	version bool
+	debug   bool
+	xml     bool
+	print   bool
+	delay   int
+	json    bool
+	html    bool
+	junit   bool
+	// file to read URLs from, one per line, or - for standard input
+	urls string
+	// config file to read options from
+	config string
+	// results of a previous test, whose failures are suppressed
+	known string
+	// format the changes between two tests are printed in: text, json, or markdown
+	output string
+	// increase in request time above which a link is slower
+	slower time.Duration
+}
+
+// Creates and returns Options with the default values.
+func NewOptions() *Options {
+	return &Options{Options: *linkt.NewOptions(""), output: "text", slower: 500 * time.Millisecond}
+}
+
+// A group of options that several commands accept.
+type group func(fs *flag.FlagSet, options *Options)
+
+// Options available to every command.
+func globalFlags(fs *flag.FlagSet, options *Options) {
+	fs.BoolVar(&options.debug, "debug", false, "Show debug logs.")
+	alias(fs, "d", "debug")
+	fs.BoolVar(&options.version, "version", false, "Show the version number.")
+	alias(fs, "v", "version")
+	fs.StringVar(&options.config, "config", "", "Read options from the config file at `<path>`. "+
+		"Defaults to linkt.yaml, linkt.yml, or linkt.toml in the working directory.")
+}
+
+// Options of the sitemap command.
+func sitemapFlags(fs *flag.FlagSet, options *Options) {
+	fs.BoolVar(&options.xml, "xml", false, "Save the sitemap to an XML file.")
+	fs.BoolVar(&options.print, "print", false, "Print the sitemap to standard output.")
+	fs.StringVar(&options.Directory, "dir", "", "The `<path>` of the directory to store the XML file in.")
+}
+
+// Options of the commands that save their results to files.
+func reportFlags(fs *flag.FlagSet, options *Options) {
+	fs.BoolVar(&options.json, "json", false, "Save the results to a JSON file.")
+	fs.BoolVar(&options.html, "html", false, "Save the results to an HTML report.")
+	fs.BoolVar(&options.junit, "junit", false, "Save the results to a JUnit XML report.")
+	fs.StringVar(&options.Directory, "dir", "", "The `<path>` of the directory to store the files in.")
+}
+
+// Options of the test command.
+func testFlags(fs *flag.FlagSet, options *Options) {
+	fs.StringVar(&options.known, "baseline", "", "Only fail on the errors that are not in the JSON results of a previous test at `<path>`.")
+}
+
+// Options of the test diff command.
+func diffFlags(fs *flag.FlagSet, options *Options) {
+	fs.StringVar(&options.output, "output", options.output, "Print the changes as `<format>`: text, json, or markdown.")
+	fs.DurationVar(&options.slower, "slower", options.slower, "Report links whose request time grew by more than this `<duration>`.")
+}
+
+// Options of the screenshot command.
+func screenshotFlags(fs *flag.FlagSet, options *Options) {
+	fs.StringVar(&options.Directory, "dir", "", "The `<path>` of the directory to save the screenshots to.")
+	fs.Var((*List)(&options.Viewports), "viewport", "The size of the viewport as `<width>x<height>`, e.g. 1280x800. Can be repeated.")
+	fs.Var((*List)(&options.Devices), "device", "The `<name>` of a device to emulate, e.g. iphone, pixel, or tablet. Can be repeated.")
+	fs.StringVar(&options.Format, "format", options.Format, "The output `<format>`: jpeg, png, webp, or pdf.")
+	fs.IntVar(&options.Quality, "quality", options.Quality, "The quality of a jpeg or webp screenshot, from `<0-100>`.")
+	fs.StringVar(&options.Capture, "capture", options.Capture, "Capture the full page or only the viewport: `<area>` is full or viewport.")
+	fs.StringVar(&options.Baseline, "baseline", "", "Compare each screenshot against the file with the same name in the directory at `<path>`.")
+	fs.Float64Var(&options.Threshold, "threshold", options.Threshold, "The mismatch `<percent>` above which a page fails.")
+	fs.Var((*List)(&options.Hide), "hide", "Hide the elements matching the CSS `<selector>`. Can be repeated.")
+	fs.Var((*List)(&options.Mask), "mask", "Cover the elements matching the CSS `<selector>` with a solid box. Can be repeated.")
+	fs.Var((*List)(&options.Scripts), "script", "Run the JavaScript file on pages whose URL matches, as `<pattern>=<path>`. Can be repeated.")
+	fs.StringVar(&options.WaitFor, "wait-for", options.WaitFor, "Wait for the CSS `<selector>` to be visible before capturing.")
+}
+
+// Options of the seo command.
+func seoFlags(fs *flag.FlagSet, options *Options) {
+	fs.Float64Var(&options.Similarity, "similarity", options.Similarity, "Report pages whose content is at least this similar, from `<0-1>`.")
+}
+
+// Options of the a11y command.
+func a11yFlags(fs *flag.FlagSet, options *Options) {
+	fs.StringVar(&options.Axe, "axe", "", "Audit with the axe.min.js at `<path>` instead of the vendored axe-core.")
+	fs.Var((*List)(&options.Scripts), "script", "Run the JavaScript file on pages whose URL matches, as `<pattern>=<path>`. Can be repeated.")
+	fs.StringVar(&options.WaitFor, "wait-for", options.WaitFor, "Wait for the CSS `<selector>` to be visible before auditing.")
+}
+
+// Options of the perf command.
+func perfFlags(fs *flag.FlagSet, options *Options) {
+	// a budget overrides the default for its metric
+	fs.Var((*List)(&options.Budgets), "budget", "Report pages over a limit, as `<metric>=<limit>`, e.g. ttfb=800ms, weight=2mb, or cls=0.1. "+
+		"The metrics are ttfb, total, size, weight, fcp, lcp, cls, and tbt. Can be repeated.")
+	fs.BoolVar(&options.Render, "render", false, "Load each page in a browser to measure its web vitals.")
+	fs.StringVar(&options.WaitFor, "wait-for", options.WaitFor, "Wait for the CSS `<selector>` to be visible before measuring.")
+}
+
+// Options of every command that crawls a site.
+func crawlFlags(fs *flag.FlagSet, options *Options) {
+	fs.StringVar(&options.urls, "urls", "", "Read the URLs to crawl from the file at `<path>`, one per line, or from standard input if it is -.")
+	fs.IntVar(&options.delay, "delay", 0, "The amount of time to delay each HTTP request, in `<milliseconds>`.")
+	fs.Float64Var(&options.Rate, "rate", 0, "Send at most this many `<requests>` per second.")
+	fs.Var((*List)(&options.Include), "include", "Only crawl the pages whose URL matches the regular expression `<pattern>`. Can be repeated.")
+	fs.Var((*List)(&options.Exclude), "exclude", "Do not crawl the pages whose URL matches the regular expression `<pattern>`, "+
+		"only check them. Can be repeated.")
+	fs.Var((*Ignores)(&options.Ignore), "ignore", "Do not check the links whose URL matches the regular expression `<pattern>`. Can be repeated.")
+	fs.Var((*List)(&options.IgnoreStatus), "ignore-status", "Do not report a link with the status `<code>`, e.g. 429 or 5xx, as broken. Can be repeated.")
+	fs.Var((*List)(&options.Headers), "header", "Send the `<name>: <value>` header to the site. Can be repeated.")
+	fs.Var((*List)(&options.Cookies), "cookie", "Send the `<name>=<value>` cookie to the site. Can be repeated.")
+	fs.StringVar(&options.CookieJar, "cookie-jar", "", "Send the cookies in the Netscape cookie jar file at `<path>`.")
+	fs.StringVar(&options.BasicAuth, "basic-auth", "", "Authenticate with basic auth as `<user>:<password>`.")
+	fs.StringVar(&options.Bearer, "bearer", "", "Authenticate with a bearer `<token>`.")
+	fs.StringVar(&options.Login, "login", "", "Log in with the form on the page at `<url>` before crawling.")
+	fs.Var((*List)(&options.LoginFields), "login-field", "Submit `<name>=<value>` with the login form. Can be repeated.")
+	fs.Var((*List)(&options.AuthHosts), "auth-host", "Send the credentials to another `<host>`. Can be repeated.")
+	fs.StringVar(&options.UserAgent, "user-agent", "", "The User-Agent `<agent>` to send. Defaults to a browser's User-Agent.")
+	fs.Var((*List)(&options.HostHeaders), "host-header", "Send a header only to a host, as `<host>=<name>: <value>`. Can be repeated.")
+	fs.IntVar(&options.MaxRedirects, "max-redirects", options.MaxRedirects, "Report redirect chains longer than this `<number>`.")
+	fs.BoolVar(&options.NoFollow, "no-follow", false, "Do not follow redirects.")
+	fs.BoolVar(&options.Insecure, "insecure", false, "Do not verify TLS certificates, and only report an untrusted certificate as a notice.")
+	fs.StringVar(&options.CABundle, "ca-bundle", "", "Trust the certificates in the PEM file at `<path>`.")
+	fs.IntVar(&options.CertDays, "cert-days", options.CertDays, "Report certificates that expire within this many `<days>`.")
+	fs.Var((*List)(&options.Resolve), "resolve", "Connect to an address for a host and port, as `<host>:<port>:<address>`. Can be repeated.")
+	fs.StringVar(&options.Proxy, "proxy", "", "Send requests through the HTTP or SOCKS5 proxy at `<url>`.")
+	fs.DurationVar(&options.Timeout, "timeout", options.Timeout, "The time limit for each request as a `<duration>`, e.g. 10s.")
+	fs.DurationVar(&options.DialTimeout, "dial-timeout", options.DialTimeout, "The time limit for connecting to a host as a `<duration>`.")
+	fs.DurationVar(&options.TLSTimeout, "tls-timeout", options.TLSTimeout, "The time limit for a TLS handshake as a `<duration>`.")
+	fs.StringVar(&options.Cache, "cache", "", "Cache responses in the directory at `<path>` and revalidate them on the next crawl.")
+	fs.DurationVar(&options.CacheTTL, "cache-ttl", options.CacheTTL, "Do not check an external link again within this `<duration>`.")
+	fs.Var((*List)(&options.StripParams), "strip-param", "Remove the query parameter `<name>` from links, in addition to tracking parameters. "+
+		"A trailing * matches any suffix. Can be repeated.")
+	fs.BoolVar(&options.LowercasePaths, "lowercase-paths", false, "Treat paths that differ only in case as the same page.")
+	fs.Var((*List)(&options.IndexFiles), "index-file", "Remove the file `<name>` from the end of paths, like index.html. Can be repeated.")
+	fs.StringVar(&options.State, "state", "", "Save the progress of the crawl to the file at `<path>` so it can be resumed.")
+	fs.BoolVar(&options.Resume, "resume", false, "Resume the crawl saved with the state option.")
+}
+
+// Defines the flag short as another name for the flag long.
+func alias(fs *flag.FlagSet, short string, long string) {
+	f := fs.Lookup(long)
+	fs.Var(f.Value, short, "")
+}
+
+// A list of values for an option that may be specified more than once.
+type List []string
+
+// Returns the values in the list separated by commas.
+func (l *List) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+// Appends value to the list.
+func (l *List) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// A list of ignore rules, each given as a pattern, or in a config file as a table
+// with a pattern, reason, and expires date.
+type Ignores []linkt.Ignore
+
+// Returns the patterns of the rules separated by commas.
+func (l *Ignores) String() string {
+	if l == nil {
+		return ""
+	}
+	patterns := []string{}
+	for _, i := range *l {
+		patterns = append(patterns, i.Pattern)
+	}
+	return strings.Join(patterns, ",")
+}
+
+// Appends a rule for the pattern in value.
+func (l *Ignores) Set(value string) error {
+	*l = append(*l, linkt.Ignore{Pattern: value})
+	return nil
+}
+
+// Appends the rule in table, which has a pattern and optionally a reason and an
+// expires date like 2025-12-31.
+func (l *Ignores) SetTable(table map[string]any) error {
+	rule := linkt.Ignore{}
+	for k, v := range table {
+		switch k {
+		case "pattern":
+			rule.Pattern, _ = v.(string)
+		case "reason":
+			rule.Reason = fmt.Sprint(v)
+		case "expires":
+			switch v := v.(type) {
+			case time.Time:
+				rule.Expires = v
+			case string:
+				expires, err := time.ParseInLocation(time.DateOnly, v, time.Local)
+				if err != nil {
+					return fmt.Errorf("invalid expires date %q, expected a date like 2025-12-31", v)
+				}
+				rule.Expires = expires
+			default:
+				return fmt.Errorf("invalid expires date %v, expected a date like 2025-12-31", v)
+			}
+		default:
+			return fmt.Errorf("unknown key %q, expected pattern, reason, or expires", k)
+		}
+	}
+	if rule.Pattern == "" {
+		return errors.New("missing pattern")
+	}
+	*l = append(*l, rule)
+	return nil
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/barreirokevin/linkt"
+)
+
+// Prints the work of a crawler to standard output.
+type printer struct {
+	linkt.NopHandler
+}
+
+// Prints the result of testing a link.
+func (printer) Checked(r linkt.Record) {
+	status := r.Status
+	if r.Result == linkt.AMBIGUOUS {
+		status = fmt.Sprintf("%s (ambiguous, possibly blocked as a bot)", status)
+	}
+	fmt.Printf(
+		"\n%s\n\tStatus\t\t\t%s%s%s\n\tRequest Time\t\t%s%s%s\n\tParent URL\t\t%s%s%s\n",
+		r.URL,
+		Color(r.Result),
+		status,
+		Reset,
+		Faint,
+		r.RequestTime,
+		Reset,
+		Faint,
+		r.ParentURL,
+		Reset,
+	)
+	if t := r.Timing; t != nil {
+		fmt.Printf(
+			"\tTiming\t\t\t%sdns %.0f ms, connect %.0f ms, tls %.0f ms, ttfb %.0f ms, download %.0f ms%s\n",
+			Faint, t.DNS, t.Connect, t.TLS, t.TTFB, t.Download, Reset,
+		)
+		fmt.Printf("\tSize\t\t\t%s%s%s\n", Faint, linkt.Format(linkt.SIZE, float64(r.Size)), Reset)
+	}
+	for i, h := range r.Redirects {
+		if i == 0 {
+			continue // the first hop is the link itself
+		}
+		fmt.Printf("\tRedirect\t\t%s%s → %s%s\n", Faint, r.Redirects[i-1].Status, h.URL, Reset)
+	}
+	if c := r.Certificate; c != nil {
+		fmt.Printf(
+			"\tCertificate\t\t%s%s, expires %s, %s%s\n",
+			Faint, c.Issuer, c.Expires.Format(time.DateOnly), c.Version, Reset,
+		)
+	}
+	for _, f := range r.Findings {
+		fmt.Printf("\tFinding\t\t\t%s%s (%s): %s%s\n", Color(f.Severity), f.Kind, f.Severity, f.Message, Reset)
+		if f.Selector != "" {
+			fmt.Printf("\t\t\t\t%s%s%s\n", Faint, f.Selector, Reset)
+		}
+		if f.HelpURL != "" {
+			fmt.Printf("\t\t\t\t%s%s%s\n", Faint, f.HelpURL, Reset)
+		}
+	}
+	if r.Suppressed != "" {
+		fmt.Printf("\tSuppressed\t\t%s%s%s\n", Faint, r.Suppressed, Reset)
+	}
+}
+
+// Prints the number of records that were suppressed for each reason, and the
+// number of records that failed, to standard output. Returns true if any record
+// failed.
+func PrintSuppressed(records []linkt.Record) bool {
+	reasons := []string{}
+	counts := map[string]int{}
+	failed := 0
+	for _, r := range records {
+		if r.Failed() {
+			failed++
+		}
+		if r.Suppressed == "" {
+			continue
+		}
+		if counts[r.Suppressed] == 0 {
+			reasons = append(reasons, r.Suppressed)
+		}
+		counts[r.Suppressed]++
+	}
+	if len(reasons) > 0 {
+		sort.SliceStable(reasons, func(i, j int) bool { return counts[reasons[i]] > counts[reasons[j]] })
+		total := 0
+		for _, n := range counts {
+			total += n
+		}
+		fmt.Printf("\n%sSuppressed %d of %d results%s\n", Yellow, total, len(records), Reset)
+		for _, reason := range reasons {
+			fmt.Printf("\t%d\t%s%s%s\n", counts[reason], Faint, reason, Reset)
+		}
+	}
+	if failed > 0 {
+		fmt.Printf("\n%s[FAILED]%s %d of %d results have errors\n", Red, Reset, failed, len(records))
+	}
+	return failed > 0
+}
+
+// Prints the result of each comparison to standard output and returns true if any
+// comparison failed.
+func PrintComparisons(comparisons []linkt.Comparison, threshold float64) bool {
+	failed := false
+	for _, c := range comparisons {
+		fmt.Printf("\n%s\n", c.URL)
+		if c.Viewport != "" {
+			fmt.Printf("\tViewport\t\t%s%s%s\n", Faint, c.Viewport, Reset)
+		}
+		switch {
+		case c.Missing:
+			fmt.Printf("\tResult\t\t\t%sNo Baseline%s\n", Yellow, Reset)
+		case c.Failed:
+			failed = true
+			fmt.Printf("\tResult\t\t\t%sChanged%s\n", Red, Reset)
+		default:
+			fmt.Printf("\tResult\t\t\t%sUnchanged%s\n", Green, Reset)
+		}
+		if !c.Missing {
+			fmt.Printf("\tMismatch\t\t%s%.2f%% (threshold %.2f%%)%s\n", Faint, c.Mismatch, threshold, Reset)
+		}
+		if c.Diff != "" {
+			fmt.Printf("\tDiff\t\t\t%s%s%s\n", Faint, c.Diff, Reset)
+		}
+	}
+	return failed
+}
+
+// Prints each title and meta description shared by more than one page, with the
+// pages that share it.
+func PrintDuplicates(duplicates []linkt.Duplicate) {
+	for _, d := range duplicates {
+		fmt.Printf("\n%s%s%s\n\t%q\n", Yellow, d.Kind, Reset, d.Value)
+		for _, u := range d.URLs {
+			fmt.Printf("\tPage\t\t\t%s%s%s\n", Faint, u, Reset)
+		}
+	}
+}
+
+// Prints each cluster of pages with identical or similar content, or that are
+// reachable at variants of the same URL, to standard output.
+func PrintClusters(clusters []linkt.Cluster) {
+	for _, c := range clusters {
+		if c.Kind == linkt.URL_VARIANT {
+			fmt.Printf("\n%s%s%s\n", Yellow, c.Kind, Reset)
+		} else {
+			fmt.Printf("\n%s%s%s (%.0f%% similar)\n", Yellow, c.Kind, Reset, c.Similarity*100)
+		}
+		for _, u := range c.URLs {
+			fmt.Printf("\tPage\t\t\t%s%s%s\n", Faint, u, Reset)
+		}
+	}
+}
+
+// Prints the n slowest and heaviest pages, and each page that is over a budget, to
+// standard output.
+func PrintPerformance(pages []linkt.Metrics, n int) {
+	fmt.Printf("\n%sSlowest pages%s\n", Yellow, Reset)
+	for _, m := range linkt.Rank(pages, n, func(m linkt.Metrics) float64 { return m.Timing.Total }) {
+		fmt.Printf(
+			"\t%s\t%s%s (ttfb %s)%s\n",
+			linkt.Format(linkt.TOTAL, m.Timing.Total), Faint, m.URL, linkt.Format(linkt.TTFB, m.Timing.TTFB), Reset,
+		)
+	}
+	fmt.Printf("\n%sHeaviest pages%s\n", Yellow, Reset)
+	for _, m := range linkt.Rank(pages, n, func(m linkt.Metrics) float64 { return float64(m.Weight) }) {
+		fmt.Printf(
+			"\t%s\t%s%s (%d resources)%s\n",
+			linkt.Format(linkt.WEIGHT, float64(m.Weight)), Faint, m.URL, len(m.Resources), Reset,
+		)
+	}
+	rendered := []linkt.Metrics{}
+	for _, m := range pages {
+		if m.Vitals != nil {
+			rendered = append(rendered, m)
+		}
+	}
+	if len(rendered) > 0 {
+		fmt.Printf("\n%sSlowest to render%s\n", Yellow, Reset)
+		for _, m := range linkt.Rank(rendered, n, func(m linkt.Metrics) float64 { return m.Vitals.LCP }) {
+			fmt.Printf(
+				"\t%s\t%s%s (fcp %s, cls %s, tbt %s)%s\n",
+				linkt.Format(linkt.LCP, m.Vitals.LCP), Faint, m.URL,
+				linkt.Format(linkt.FCP, m.Vitals.FCP), linkt.Format(linkt.CLS, m.Vitals.CLS),
+				linkt.Format(linkt.TBT, m.Vitals.TBT), Reset,
+			)
+		}
+	}
+	for _, m := range pages {
+		if len(m.Findings) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s\n", m.URL)
+		for _, f := range m.Findings {
+			fmt.Printf("\tFinding\t\t\t%s%s (%s): %s%s\n", Color(f.Severity), f.Kind, f.Severity, f.Message, Reset)
+		}
+	}
+}
+
+// Returns the color used to print a result or the severity of a finding.
+func Color(result string) string {
+	switch result {
+	case linkt.INFO, linkt.NOTICE:
+		return Blue
+	case linkt.OK:
+		return Green
+	case linkt.IGNORED:
+		return Faint
+	case linkt.REDIRECT, linkt.WARNING:
+		return Yellow
+	case linkt.AMBIGUOUS:
+		return Purple
+	default:
+		return Red
+	}
+}
+
+// Prints the changes between two tests to standard output, with the links that
+// broke or got slower first.
+func PrintDiff(diff *linkt.Diff) {
+	sections := []struct {
+		title   string
+		color   string
+		changes []linkt.Change
+		// whether the request times are printed instead of the statuses
+		timed bool
+	}{
+		{"Newly broken", Red, diff.Broken, false},
+		{"Slower", Red, diff.Slower, true},
+		{"Fixed", Green, diff.Fixed, false},
+		{"Status changed", Yellow, diff.Changed, false},
+		{"New pages", Blue, diff.Added, false},
+		{"Removed pages", Blue, diff.Removed, false},
+	}
+	changed := false
+	for _, s := range sections {
+		if len(s.changes) == 0 {
+			continue
+		}
+		changed = true
+		fmt.Printf("\n%s%s (%d)%s\n", s.color, s.title, len(s.changes), Reset)
+		for _, c := range s.changes {
+			switch {
+			case s.timed:
+				fmt.Printf(
+					"\t%s\t%s%.0f ms → %.0f ms (+%.0f ms)%s\n",
+					c.URL, Faint, c.BeforeTime, c.AfterTime, c.AfterTime-c.BeforeTime, Reset,
+				)
+			case c.Before == "":
+				fmt.Printf("\t%s\t%s%s%s\n", c.URL, Faint, c.After, Reset)
+			case c.After == "":
+				fmt.Printf("\t%s\t%s%s%s\n", c.URL, Faint, c.Before, Reset)
+			default:
+				fmt.Printf("\t%s\t%s%s → %s%s\n", c.URL, Faint, c.Before, c.After, Reset)
+			}
+		}
+	}
+	if !changed {
+		fmt.Printf("\n%sNo changes%s\n", Green, Reset)
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/barreirokevin/linkt"
+)
+
+// The template of the HTML report.
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>linkt {{.Command}} {{.URL}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2rem; }
+th, td { border: 1px solid #ddd; padding: .4rem; text-align: left; vertical-align: top; }
+code { font-size: .85rem; }
+.error, .broken { color: #c62828; } .warning, .redirect { color: #ef6c00; } .notice, .info { color: #1565c0; } .ok { color: #2e7d32; } .ignored { color: #757575; }
+</style>
+</head>
+<body>
+<h1>linkt {{.Command}} {{.URL}}</h1>
+<p>{{len .Records}} pages, <span class="error">{{.Errors}} errors</span>, <span class="warning">{{.Warnings}} warnings</span>, <span class="notice">{{.Notices}} notices</span>{{if .Suppressed}}, <span class="ignored">{{.Suppressed}} suppressed</span>{{end}}</p>
+{{range .Records}}
+<h2><a href="{{.URL}}">{{.URL}}</a></h2>
+<p class="{{.Result}}">{{.Status}}</p>
+{{if .Suppressed}}<p class="ignored">Suppressed: {{.Suppressed}}</p>{{end}}
+{{if .Findings}}
+<table>
+<tr><th>Severity</th><th>Kind</th><th>Message</th><th>Element</th></tr>
+{{range .Findings}}
+<tr>
+<td class="{{.Severity}}">{{.Severity}}{{if .Impact}} ({{.Impact}}){{end}}</td>
+<td>{{if .HelpURL}}<a href="{{.HelpURL}}">{{.Kind}}</a>{{else}}{{.Kind}}{{end}}</td>
+<td>{{.Message}}</td>
+<td>{{if .Selector}}<code>{{.Selector}}</code>{{end}}</td>
+</tr>
+{{end}}
+</table>
+{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+// A JUnit XML report with a test case for each record.
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// Returns true if a report that must be saved to the directory is requested.
+func (app *App) reporting() bool {
+	return app.options.json || app.options.html || app.options.junit
+}
+
+// Returns the name of the files the reports of the command are saved to, without
+// an extension.
+func (app *App) reportName(prefix string) string {
+	processedURL := strings.ReplaceAll(app.url, "/", "-")
+	processedURL = strings.ReplaceAll(processedURL, ":", "")
+	return prefix + processedURL
+}
+
+// Saves the HTML and JUnit reports requested with the html and junit options for
+// records to files named name in the directory specified.
+func (app *App) report(name string, records []linkt.Record) error {
+	if !app.options.html && !app.options.junit {
+		return nil
+	}
+	if err := os.MkdirAll(app.options.Directory, os.ModePerm); err != nil {
+		return err
+	}
+	if app.options.html {
+		if err := WriteHTML(filepath.Join(app.options.Directory, name+".html"), app.command.Name, app.url, records); err != nil {
+			return err
+		}
+	}
+	if app.options.junit {
+		if err := WriteJUnit(filepath.Join(app.options.Directory, name+".junit.xml"), app.command.Name, records); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Writes an HTML report of the records of command for the site at url to the file
+// at path.
+func WriteHTML(path string, command string, url string, records []linkt.Record) error {
+	data := struct {
+		Command    string
+		URL        string
+		Records    []linkt.Record
+		Errors     int
+		Warnings   int
+		Notices    int
+		Suppressed int
+	}{Command: command, URL: url, Records: records}
+	for _, r := range records {
+		// the findings of a suppressed record are not counted
+		if r.Suppressed != "" {
+			data.Suppressed++
+			continue
+		}
+		for _, f := range r.Findings {
+			switch f.Severity {
+			case linkt.ERROR:
+				data.Errors++
+			case linkt.WARNING:
+				data.Warnings++
+			default:
+				data.Notices++
+			}
+		}
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return reportTemplate.Execute(file, data)
+}
+
+// Writes a JUnit XML report of the records of command to the file at path. Each
+// record is a test case that fails if it has a finding with the severity ERROR,
+// such as a broken link, and is skipped if it is suppressed.
+func WriteJUnit(path string, command string, records []linkt.Record) error {
+	suite := junitSuite{Name: "linkt " + command, Tests: len(records), Cases: []junitCase{}}
+	for _, r := range records {
+		c := junitCase{ClassName: command, Name: r.URL}
+		failures := []string{}
+		others := []string{}
+		for _, f := range r.Findings {
+			line := fmt.Sprintf("%s (%s): %s", f.Kind, f.Severity, f.Message)
+			if f.Selector != "" {
+				line += fmt.Sprintf(" [%s]", f.Selector)
+			}
+			if f.HelpURL != "" {
+				line += " " + f.HelpURL
+			}
+			if f.Severity == linkt.ERROR {
+				failures = append(failures, line)
+			} else {
+				others = append(others, line)
+			}
+		}
+		switch {
+		case r.Suppressed != "":
+			suite.Skipped++
+			c.Skipped = &junitSkipped{Message: r.Suppressed}
+			others = append(failures, others...)
+		case len(failures) > 0:
+			suite.Failures++
+			c.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d of %d findings are errors", len(failures), len(failures)+len(others)),
+				Type:    linkt.ERROR,
+				Text:    strings.Join(failures, "\n"),
+			}
+		}
+		c.SystemOut = strings.Join(others, "\n")
+		suite.Cases = append(suite.Cases, c)
+	}
+	data, err := xml.MarshalIndent(junitSuites{Suites: []junitSuite{suite}}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append([]byte(xml.Header), data...), 0666)
+}
+
+// Returns the changes between two tests as Markdown, e.g. for a comment on a pull
+// request.
+func Markdown(diff *linkt.Diff) string {
+	var b strings.Builder
+	b.WriteString("## linkt test diff\n\n")
+	fmt.Fprintf(
+		&b, "**%d** newly broken, **%d** slower, **%d** fixed, **%d** status changed, **%d** new, **%d** removed\n",
+		len(diff.Broken), len(diff.Slower), len(diff.Fixed), len(diff.Changed), len(diff.Added), len(diff.Removed),
+	)
+	table := func(title string, changes []linkt.Change, header string, row func(c linkt.Change) []string) {
+		if len(changes) == 0 {
+			return
+		}
+		columns := strings.Split(header, "|")
+		fmt.Fprintf(&b, "\n### %s\n\n| %s |\n|%s\n", title, strings.Join(columns, " | "), strings.Repeat(" --- |", len(columns)))
+		for _, c := range changes {
+			cells := row(c)
+			for i, cell := range cells {
+				cells[i] = strings.ReplaceAll(cell, "|", "\\|")
+			}
+			fmt.Fprintf(&b, "| %s |\n", strings.Join(cells, " | "))
+		}
+	}
+	status := func(c linkt.Change) []string {
+		return []string{c.URL, c.Before, c.After, c.ParentURL}
+	}
+	table("Newly broken", diff.Broken, "URL|Before|After|Parent URL", status)
+	table("Slower", diff.Slower, "URL|Before|After|Increase", func(c linkt.Change) []string {
+		return []string{
+			c.URL,
+			fmt.Sprintf("%.0f ms", c.BeforeTime),
+			fmt.Sprintf("%.0f ms", c.AfterTime),
+			fmt.Sprintf("+%.0f ms", c.AfterTime-c.BeforeTime),
+		}
+	})
+	table("Fixed", diff.Fixed, "URL|Before|After|Parent URL", status)
+	table("Status changed", diff.Changed, "URL|Before|After|Parent URL", status)
+	table("New pages", diff.Added, "URL|Status|Parent URL", func(c linkt.Change) []string {
+		return []string{c.URL, c.After, c.ParentURL}
+	})
+	table("Removed pages", diff.Removed, "URL|Status|Parent URL", func(c linkt.Change) []string {
+		return []string{c.URL, c.Before, c.ParentURL}
+	})
+	return b.String()
+}
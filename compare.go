@@ -0,0 +1,140 @@
+package linkt
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "golang.org/x/image/webp"
+)
+
+// The maximum difference between a channel of two pixels, out of 0xffff, for the
+// pixels to be considered the same. It absorbs the noise of lossy formats.
+const tolerance = 0x0f00
+
+// The result of comparing a screenshot against its baseline.
+type Comparison struct {
+	URL      string `json:"url"`
+	Viewport string `json:"viewport,omitempty"`
+	File     string `json:"file"`
+	// percentage of pixels that differ from the baseline
+	Mismatch float64 `json:"mismatch"`
+	// path of the image that highlights the changed regions
+	Diff string `json:"diff,omitempty"`
+	// true if the baseline directory does not contain the screenshot
+	Missing bool `json:"missing,omitempty"`
+	Failed  bool `json:"failed"`
+}
+
+// Compares the screenshot filename in directory dir against the file with the same
+// name in the baseline directory. If the screenshots differ, an image that
+// highlights the changed regions is written to the diff directory inside dir.
+// The comparison fails if the mismatch percentage is greater than threshold.
+func Compare(baseline string, dir string, filename string, threshold float64) (Comparison, error) {
+	c := Comparison{File: filename}
+	want, err := decode(filepath.Join(baseline, filename))
+	if errors.Is(err, os.ErrNotExist) {
+		c.Missing = true
+		return c, nil
+	} else if err != nil {
+		return c, err
+	}
+	got, err := decode(filepath.Join(dir, filename))
+	if err != nil {
+		return c, err
+	}
+
+	// compare every pixel of the area covered by either screenshot
+	bounds := want.Bounds().Union(got.Bounds())
+	diff := image.NewRGBA(bounds)
+	changed := image.Rectangle{}
+	count := 0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			p := image.Pt(x, y)
+			if p.In(want.Bounds()) && p.In(got.Bounds()) && same(want.At(x, y), got.At(x, y)) {
+				// fade the unchanged pixels so the changes stand out
+				g := color.GrayModel.Convert(got.At(x, y)).(color.Gray)
+				g.Y = 0xff - (0xff-g.Y)/4
+				diff.Set(x, y, g)
+				continue
+			}
+			count++
+			diff.Set(x, y, color.RGBA{R: 0xff, A: 0xff})
+			changed = changed.Union(image.Rect(x, y, x+1, y+1))
+		}
+	}
+	if bounds.Empty() {
+		return c, nil
+	}
+	c.Mismatch = float64(count) / float64(bounds.Dx()*bounds.Dy()) * 100
+	c.Failed = c.Mismatch > threshold
+	if count == 0 {
+		return c, nil
+	}
+
+	// outline the changed region and write the diff image
+	outline(diff, changed.Inset(-4).Intersect(bounds), color.RGBA{R: 0xff, G: 0x80, A: 0xff})
+	c.Diff = filepath.Join(dir, "diff", strings.TrimSuffix(filename, filepath.Ext(filename))+".png")
+	if err := os.MkdirAll(filepath.Dir(c.Diff), os.ModePerm); err != nil {
+		return c, err
+	}
+	file, err := os.Create(c.Diff)
+	if err != nil {
+		return c, err
+	}
+	defer file.Close()
+	return c, png.Encode(file, diff)
+}
+
+// Returns the image decoded from the file at path.
+func decode(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s: %w", path, err)
+	}
+	return img, nil
+}
+
+// Returns true if each channel of a and b differs by no more than the tolerance.
+func same(a color.Color, b color.Color) bool {
+	r1, g1, b1, a1 := a.RGBA()
+	r2, g2, b2, a2 := b.RGBA()
+	for _, d := range []int64{
+		int64(r1) - int64(r2),
+		int64(g1) - int64(g2),
+		int64(b1) - int64(b2),
+		int64(a1) - int64(a2),
+	} {
+		if d > tolerance || d < -tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// Draws a two pixel wide outline of rectangle r on img.
+func outline(img draw.Image, r image.Rectangle, c color.Color) {
+	for x := r.Min.X; x < r.Max.X; x++ {
+		for _, y := range []int{r.Min.Y, r.Min.Y + 1, r.Max.Y - 2, r.Max.Y - 1} {
+			img.Set(x, y, c)
+		}
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for _, x := range []int{r.Min.X, r.Min.X + 1, r.Max.X - 2, r.Max.X - 1} {
+			img.Set(x, y, c)
+		}
+	}
+}
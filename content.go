@@ -0,0 +1,279 @@
+package linkt
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash/fnv"
+	"math/bits"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// Kind of cluster of pages reported by the content check
+const DUPLICATE_CONTENT = "duplicate-content"
+const SIMILAR_CONTENT = "similar-content"
+const URL_VARIANT = "url-variant"
+
+// The number of words in each shingle a SimHash is computed from.
+const shingle = 3
+
+// Elements whose text is not part of the main content of a page.
+var boilerplate = Set[string, int]{
+	"script": 0, "style": 0, "noscript": 0, "template": 0, "svg": 0,
+	"nav": 0, "header": 0, "footer": 0, "aside": 0, "form": 0,
+}
+
+// The exact hash and SimHash of the main text of a page.
+type Fingerprint struct {
+	URL     string `json:"url"`
+	Hash    string `json:"hash"`
+	SimHash uint64 `json:"simhash"`
+	Words   int    `json:"words"`
+}
+
+// Pages with identical or similar content, or that are reachable at variants of
+// the same URL.
+type Cluster struct {
+	Kind string `json:"kind"`
+	// lowest similarity between two pages that were grouped, from 0 to 1
+	Similarity float64  `json:"similarity,omitempty"`
+	URLs       []string `json:"urls"`
+}
+
+// Fingerprints the main text of each internal HTML page, so pages with the same or
+// similar content can be grouped across the crawl.
+type contentCheck struct {
+	fingerprints []Fingerprint
+	// final URLs of the pages that were fingerprinted
+	seen Set[string, int]
+}
+
+// Returns a content check that has not seen any page.
+func newContentCheck() *contentCheck {
+	return &contentCheck{fingerprints: []Fingerprint{}, seen: Set[string, int]{}}
+}
+
+func (c *contentCheck) Name() string {
+	return "content"
+}
+
+func (c *contentCheck) Check(page Page) []Finding {
+	doc := page.Document()
+	if doc == nil || page.response == nil || page.response.StatusCode < 200 || page.response.StatusCode > 299 {
+		return nil
+	}
+	// a page is fingerprinted at the URL it was redirected to, so it is only
+	// fingerprinted once
+	link := page.URL().String()
+	if page.response.Request != nil {
+		link = page.response.Request.URL.String()
+	}
+	if c.seen.Contains(link) {
+		return nil
+	}
+	c.seen[link] = 0
+	words := strings.FieldsFunc(strings.ToLower(content(doc)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) == 0 {
+		return nil
+	}
+	sum := sha256.Sum256([]byte(strings.Join(words, " ")))
+	c.fingerprints = append(c.fingerprints, Fingerprint{
+		URL:     link,
+		Hash:    hex.EncodeToString(sum[:]),
+		SimHash: simhash(words),
+		Words:   len(words),
+	})
+	return nil
+}
+
+// Returns the clusters of pages with identical content, with content at least
+// threshold similar, and that are reachable at variants of the same URL with such
+// content, sorted by the number of pages in them.
+func (c *contentCheck) Clusters(threshold float64) []Cluster {
+	clusters := []Cluster{}
+
+	// pages with the same text
+	groups := map[string][]Fingerprint{}
+	hashes := []string{}
+	for _, f := range c.fingerprints {
+		if _, ok := groups[f.Hash]; !ok {
+			hashes = append(hashes, f.Hash)
+		}
+		groups[f.Hash] = append(groups[f.Hash], f)
+	}
+	for _, h := range hashes {
+		if len(groups[h]) > 1 {
+			clusters = append(clusters, Cluster{Kind: DUPLICATE_CONTENT, Similarity: 1, URLs: urls(groups[h])})
+		}
+	}
+
+	// pages with similar text, grouped transitively
+	parent := make([]int, len(hashes))
+	lowest := make([]float64, len(hashes))
+	for i := range parent {
+		parent[i] = i
+		lowest[i] = 1
+	}
+	root := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	for i := range hashes {
+		for j := i + 1; j < len(hashes); j++ {
+			a, b := groups[hashes[i]][0], groups[hashes[j]][0]
+			if min(a.Words, b.Words) < shingle {
+				continue
+			}
+			s := similarity(a.SimHash, b.SimHash)
+			if s < threshold {
+				continue
+			}
+			ri, rj := root(i), root(j)
+			if ri != rj {
+				parent[rj] = ri
+				lowest[ri] = min(lowest[ri], lowest[rj])
+			}
+			lowest[ri] = min(lowest[ri], s)
+		}
+	}
+	similar := map[int][]Fingerprint{}
+	members := map[int]int{}
+	for i, h := range hashes {
+		r := root(i)
+		similar[r] = append(similar[r], groups[h]...)
+		members[r]++
+	}
+	for i := range hashes {
+		if members[i] > 1 {
+			clusters = append(clusters, Cluster{Kind: SIMILAR_CONTENT, Similarity: lowest[i], URLs: urls(similar[i])})
+		}
+	}
+
+	// pages with the same or similar text reachable at URLs that differ only by a
+	// trailing slash, case, or query
+	variants := map[string][][]Fingerprint{}
+	keys := []string{}
+	for _, f := range c.fingerprints {
+		k := variant(f.URL)
+		if _, ok := variants[k]; !ok {
+			keys = append(keys, k)
+		}
+		matched := false
+		for i, group := range variants[k] {
+			if alike(group[0], f, threshold) {
+				variants[k][i] = append(group, f)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			variants[k] = append(variants[k], []Fingerprint{f})
+		}
+	}
+	for _, k := range keys {
+		for _, group := range variants[k] {
+			if len(group) > 1 {
+				clusters = append(clusters, Cluster{Kind: URL_VARIANT, URLs: urls(group)})
+			}
+		}
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return len(clusters[i].URLs) > len(clusters[j].URLs)
+	})
+	return clusters
+}
+
+// Returns the text in the main content of the page, which is its main or article
+// element, or its body without the navigation, header, footer, and scripts.
+func content(doc *html.Node) string {
+	n := find(doc, "main")
+	if n == nil {
+		n = find(doc, "article")
+	}
+	if n == nil {
+		n = find(doc, "body")
+	}
+	if n == nil {
+		n = doc
+	}
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			b.WriteString(n.Data)
+			b.WriteString(" ")
+		case n.Type == html.ElementNode && boilerplate.Contains(n.Data):
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+// Returns the SimHash of words, computed from the hashes of each run of shingle
+// words, or of each word if there are fewer.
+func simhash(words []string) uint64 {
+	var weights [64]int
+	size := min(shingle, len(words))
+	for i := 0; i+size <= len(words); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:i+size], " ")))
+		sum := h.Sum64()
+		for bit := range weights {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+	var hash uint64
+	for bit, w := range weights {
+		if w > 0 {
+			hash |= 1 << bit
+		}
+	}
+	return hash
+}
+
+// Returns the similarity of two SimHashes, from 0 to 1.
+func similarity(a uint64, b uint64) float64 {
+	return 1 - float64(bits.OnesCount64(a^b))/64
+}
+
+// Returns true if a and b have the same text, or text at least threshold similar.
+func alike(a Fingerprint, b Fingerprint, threshold float64) bool {
+	if a.Hash == b.Hash {
+		return true
+	}
+	return min(a.Words, b.Words) >= shingle && similarity(a.SimHash, b.SimHash) >= threshold
+}
+
+// Returns link without its query, fragment, and trailing slash, in lowercase.
+func variant(link string) string {
+	link, _, _ = strings.Cut(link, "#")
+	link, _, _ = strings.Cut(link, "?")
+	return strings.ToLower(strings.TrimSuffix(link, "/"))
+}
+
+// Returns the URLs of the fingerprints.
+func urls(fingerprints []Fingerprint) []string {
+	found := []string{}
+	for _, f := range fingerprints {
+		found = append(found, f.URL)
+	}
+	return found
+}
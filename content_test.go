@@ -0,0 +1,84 @@
+package linkt
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+// Returns n words of text, with the word at each index in changed replaced.
+func words(n int, changed ...int) string {
+	w := make([]string, n)
+	for i := range w {
+		w[i] = fmt.Sprintf("word%d", i)
+	}
+	for _, i := range changed {
+		w[i] = "changed"
+	}
+	return strings.Join(w, " ")
+}
+
+func TestClusters(t *testing.T) {
+	text := words(200)
+	tests := []struct {
+		name string
+		// main text of the page at each URL, in the order they are checked
+		pages [][2]string
+		// kind and URLs of each cluster
+		want []string
+	}{
+		{
+			"duplicate",
+			[][2]string{{"/a", text}, {"/b", text}, {"/c", "unrelated words on another page"}},
+			[]string{DUPLICATE_CONTENT + " [/a /b]"},
+		},
+		{
+			"similar",
+			[][2]string{{"/a", text}, {"/b", words(200, 100)}, {"/c", "something else entirely"}},
+			[]string{SIMILAR_CONTENT + " [/a /b]"},
+		},
+		{
+			"url variants with the same content",
+			[][2]string{{"/a", text}, {"/A/", text}},
+			[]string{DUPLICATE_CONTENT + " [/a /A/]", URL_VARIANT + " [/a /A/]"},
+		},
+		{
+			"url variants with other content",
+			[][2]string{{"/a", text}, {"/a?page=2", "a different page of results"}},
+			[]string{},
+		},
+		{
+			"some url variants with the same content",
+			[][2]string{{"/a", text}, {"/a?page=2", "other results"}, {"/a?ref=x", text}},
+			[]string{DUPLICATE_CONTENT + " [/a /a?ref=x]", URL_VARIANT + " [/a /a?ref=x]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newContentCheck()
+			for _, p := range tt.pages {
+				link, _ := url.Parse("https://example.com" + p[0])
+				page := *NewPage(link)
+				page.doc, _ = html.Parse(strings.NewReader("<main>" + p[1] + "</main>"))
+				page.response = &http.Response{StatusCode: http.StatusOK, Request: page.request}
+				c.Check(page)
+			}
+			got := []string{}
+			for _, cluster := range c.Clusters(0.9) {
+				urls := []string{}
+				for _, u := range cluster.URLs {
+					urls = append(urls, strings.TrimPrefix(u, "https://example.com"))
+				}
+				got = append(got, fmt.Sprintf("%s %v", cluster.Kind, urls))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Clusters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
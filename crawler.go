@@ -0,0 +1,717 @@
+package linkt
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// A crawler with capabilities such as building a sitemap, testing links,
+// and taking screenshots for a site.
+type Crawler struct {
+	client  *http.Client
+	options *Options
+	logger  *slog.Logger
+	// receives the events of the crawl
+	handler Handler
+	// normal form of each link that was found
+	visited *Set[string, int]
+	// rewrites each link to its normal form before it is looked up in visited
+	normalizer *Normalizer
+	// links that are requested and pages that are crawled
+	scope   *Scope
+	sitemap *Sitemap
+	current Page
+	// viewports to take a screenshot of each page with
+	viewports []Viewport
+	// scripts to run on a page before taking its screenshot
+	scripts []Script
+	// credentials sent to the hosts the crawler authenticates with
+	auth *Auth
+	// whether the HTTPS version of an insecure link exists
+	upgrades map[string]bool
+	// roots that certificates are verified against, nil for the system roots
+	roots *x509.CertPool
+	// hosts whose certificate was inspected
+	inspected *Set[string, int]
+	// connects to a host, honoring the resolve option
+	dial Dial
+	// time the last request was sent, to keep to the rate option
+	last time.Time
+	// responses from previous crawls, nil if caching is disabled
+	cache *Cache
+	// pages that were found but not visited yet, the last one is visited next
+	frontier []*Node[Page]
+	// checks run on each page that is tested
+	checks []Check
+	// audits each page for SEO problems, nil unless the action is SEO
+	seo *seoCheck
+	// fingerprints the content of each page, nil unless the action is SEO
+	content *contentCheck
+	// source of axe-core, empty unless the action is A11Y
+	axe string
+	// limits on the metrics of each page, and the metrics measured so far
+	budgets Budgets
+	metrics []Metrics
+	// errors found in a previous test, which are suppressed when found again
+	known Known
+	// results of testing each link
+	records []Record
+	// maps each screenshot file to the page it was taken of
+	manifest *Manifest
+	// results of comparing screenshots against a baseline
+	comparisons []Comparison
+}
+
+// Returns a new crawler with an HTTP client that performs the action in options and
+// reports its work to handler, which may be nil.
+func NewCrawler(options *Options, handler Handler) (*Crawler, error) {
+	if handler == nil {
+		handler = NopHandler{}
+	}
+	logger := options.Logger
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	c := &http.Client{
+		Timeout: options.Timeout,
+	}
+	headers, err := NewHeaders(options)
+	if err != nil {
+		return nil, fmt.Errorf("invalid header: %w", err)
+	}
+	transport, err := NewTransport(options)
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection options: %w", err)
+	}
+	c.Transport = &headerTransport{base: transport, headers: headers}
+	scope, err := NewScope(options)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scope: %w", err)
+	}
+	crawler := &Crawler{
+		client:     c,
+		options:    options,
+		logger:     logger,
+		handler:    handler,
+		visited:    &Set[string, int]{},
+		normalizer: NewNormalizer(options),
+		scope:      scope,
+		sitemap:    nil,
+		current:    Page{},
+		upgrades:   map[string]bool{},
+		roots:      transport.TLSClientConfig.RootCAs,
+		dial:       transport.DialContext,
+		inspected:  &Set[string, int]{},
+		checks:     builtinChecks(options, scope),
+		known:      NewKnown(options.Known),
+		records:    []Record{},
+		manifest:   NewManifest(),
+	}
+	if options.Action == SEO {
+		crawler.seo = newSEOCheck()
+		crawler.content = newContentCheck()
+		crawler.checks = append(crawler.checks, crawler.seo, crawler.content)
+	}
+	if options.Action == A11Y {
+		crawler.axe, err = AxeSource(options.Axe)
+		if err != nil {
+			return nil, fmt.Errorf("invalid axe-core: %w", err)
+		}
+		crawler.scripts, err = NewScripts(options.Scripts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid script: %w", err)
+		}
+	}
+	if options.Action == PERF {
+		crawler.budgets, err = NewBudgets(options.Budgets)
+		if err != nil {
+			return nil, fmt.Errorf("invalid budget: %w", err)
+		}
+	}
+	if options.Action == SCREENSHOT {
+		if err := ValidateScreenshot(options); err != nil {
+			return nil, fmt.Errorf("invalid screenshot options: %w", err)
+		}
+		crawler.viewports, err = NewViewports(options.Viewports, options.Devices)
+		if err != nil {
+			return nil, fmt.Errorf("invalid viewport or device: %w", err)
+		}
+		crawler.scripts, err = NewScripts(options.Scripts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid script: %w", err)
+		}
+	}
+	if options.Cache != "" {
+		crawler.cache, err = NewCache(options.Cache, options.CacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("error creating the cache: %w", err)
+		}
+	}
+	// a redirect chain stops at a loop, and credentials are only sent to allowed
+	// hosts when a request is redirected
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if options.NoFollow || len(via) >= maxFollow {
+			return http.ErrUseLastResponse
+		}
+		for _, v := range via {
+			if v.URL.String() == req.URL.String() {
+				return http.ErrUseLastResponse
+			}
+		}
+		if crawler.auth != nil {
+			crawler.auth.Apply(req)
+		}
+		return nil
+	}
+	return crawler, nil
+}
+
+// Adds checks to run on each page that is tested, after the built-in checks.
+func (crawler *Crawler) Register(checks ...Check) {
+	crawler.checks = append(crawler.checks, checks...)
+}
+
+// Returns the results of testing each link.
+func (crawler *Crawler) Records() []Record {
+	return crawler.records
+}
+
+// Returns the titles and meta descriptions shared by more than one page, which are
+// only collected if the action is SEO.
+func (crawler *Crawler) Duplicates() []Duplicate {
+	if crawler.seo == nil {
+		return []Duplicate{}
+	}
+	return crawler.seo.Duplicates()
+}
+
+// Returns the clusters of pages with identical or similar content, or that are
+// reachable at variants of the same URL, found by an SEO audit.
+func (crawler *Crawler) Clusters() []Cluster {
+	if crawler.content == nil {
+		return []Cluster{}
+	}
+	return crawler.content.Clusters(crawler.options.Similarity)
+}
+
+// Returns the manifest of the screenshots that were taken.
+func (crawler *Crawler) Manifest() *Manifest {
+	return crawler.manifest
+}
+
+// Returns the results of comparing the screenshots against the baseline.
+func (crawler *Crawler) Comparisons() []Comparison {
+	return crawler.comparisons
+}
+
+// Enables the crawler to crawl starting from the root URL. The crawl stops when ctx
+// is done, and the sitemap built so far is returned with the error of ctx.
+func (crawler *Crawler) Crawl(ctx context.Context, root *url.URL) (*Sitemap, error) {
+	crawler.sitemap = NewSitemap(crawler.logger)
+	// authenticate before crawling
+	auth, err := NewAuth(crawler.options, root)
+	if err != nil {
+		return crawler.sitemap, fmt.Errorf("invalid credentials: %w", err)
+	}
+	crawler.auth = auth
+	crawler.client.Jar = auth.jar
+	if crawler.options.Login != "" {
+		if err := auth.Login(ctx, crawler.client, crawler.options.Login, crawler.options.LoginFields); err != nil {
+			return crawler.sitemap, fmt.Errorf("error logging in at %s: %w", crawler.options.Login, err)
+		}
+		crawler.logger.Info("logged in", "url", crawler.options.Login)
+	}
+	if err := crawler.start(root); err != nil {
+		return crawler.sitemap, err
+	}
+
+	// visit the pages in the frontier until there are none left
+	for visits := 1; len(crawler.frontier) > 0; visits++ {
+		node := crawler.frontier[len(crawler.frontier)-1]
+		crawler.frontier = crawler.frontier[:len(crawler.frontier)-1]
+		if err := crawler.walk(ctx, crawler.sitemap, node); err != nil {
+			if ctx.Err() != nil {
+				// visit the page again when the crawl is resumed
+				crawler.frontier = append(crawler.frontier, node)
+				crawler.checkpoint()
+				return crawler.sitemap, ctx.Err()
+			}
+			return crawler.sitemap, err
+		}
+		crawler.handler.Visited(crawler.current)
+		if ctx.Err() != nil {
+			crawler.checkpoint()
+			return crawler.sitemap, ctx.Err()
+		}
+		if visits%checkpointEvery == 0 {
+			crawler.checkpoint()
+		}
+	}
+	crawler.checkpoint()
+	return crawler.sitemap, nil
+}
+
+// Adds the root page to the sitemap and the frontier. If the resume option is
+// specified, the crawl saved with the state option is restored instead.
+func (crawler *Crawler) start(root *url.URL) error {
+	options := crawler.options
+	if options.Resume {
+		if options.State == "" {
+			return errors.New("the resume option requires the state option")
+		}
+		state, err := LoadState(options.State)
+		switch {
+		case err == nil:
+			if err := crawler.restore(state, root); err != nil {
+				return fmt.Errorf("error resuming the crawl: %w", err)
+			}
+			crawler.logger.Info(
+				"resumed the crawl",
+				"path", options.State,
+				"pages", len(state.Pages),
+				"remaining", len(state.Frontier),
+			)
+			return nil
+		case errors.Is(err, fs.ErrNotExist):
+			crawler.logger.Info("no saved crawl, starting a new crawl", "path", options.State)
+		default:
+			return fmt.Errorf("error resuming the crawl: %w", err)
+		}
+	}
+	page := *NewPage(root)
+	page.kind = Internal
+	page.parentURL = root.String()
+	node, err := crawler.sitemap.AddRoot(page)
+	if err != nil {
+		return fmt.Errorf("error adding root page %s to the sitemap: %w", page.request.URL.String(), err)
+	}
+	(*crawler.visited)[crawler.normalizer.Normalize(root.String())] = Internal
+	crawler.frontier = []*Node[Page]{node}
+	return nil
+}
+
+// Enables the crawler to walk through the elements on a page. As the crawler walks
+// through the elements on the page it builds a sitemap with the anchor tags it
+// encounters, and adds the pages it finds to the frontier to be visited next. The
+// crawler reports its work based on the action specified.
+func (crawler *Crawler) walk(ctx context.Context, sitemap *Sitemap, node *Node[Page]) error {
+	// fetch a page
+	crawler.current = node.GetElement()
+	if err := crawler.fetch(ctx); err != nil {
+		return err
+	}
+
+	// return early if node is external or is not an HTML page
+	// we don't need to scrape anchor tags from an external node
+	if crawler.current.response == nil {
+		if crawler.current.err != nil || crawler.current.ignored != nil {
+			return crawler.process(ctx)
+		}
+		return nil
+	}
+	contentType := crawler.current.response.Header.Get("Content-Type")
+	if crawler.current.kind != Internal || (contentType != "" && !strings.Contains(contentType, "html")) {
+		drain(crawler.current.response)
+		return crawler.process(ctx)
+	}
+
+	// parse page to get tree
+	doc, err := html.Parse(crawler.current.response.Body)
+	drain(crawler.current.response)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("error parsing page %s: %w", crawler.current.request.URL.String(), err)
+	}
+
+	// collect each url on the current page and process the page with its findings
+	crawler.current.doc = doc
+	crawler.collect(ctx, doc)
+	if err := crawler.process(ctx); err != nil {
+		// the links never reach the frontier, so they are not visited yet and are
+		// collected again when the page is visited again
+		for link := range crawler.current.links {
+			delete(*crawler.visited, crawler.normalizer.Normalize(link))
+		}
+		clear(crawler.current.links)
+		return err
+	}
+
+	// populate the tree with Set of internal and external links
+	children := []*Node[Page]{}
+	for p, t := range crawler.current.links {
+		link, err := url.Parse(p)
+		if err != nil {
+			crawler.logger.Error(
+				"error parsing a page URL",
+				"page", p,
+				"error", err,
+			)
+			continue
+		}
+		page := *NewPage(link)
+		page.kind = t
+		// a page outside the scope is checked like an external page, but not crawled
+		if t == Internal && !crawler.scope.Crawled(link.String()) {
+			crawler.logger.Info("excluded a page", "page", link.String())
+			page.kind = External
+		}
+		page.parentURL = node.GetElement().request.URL.String()
+		children = append(children, sitemap.AddChild(node, page))
+	}
+
+	for _, c := range children {
+		crawler.handler.Discovered(c.GetElement())
+	}
+	// visit the children in the order they were found
+	for i := len(children) - 1; i >= 0; i-- {
+		crawler.frontier = append(crawler.frontier, children[i])
+	}
+	return nil
+}
+
+// collect is recursively called in the walk function to visit each anchor, img, or
+// script tag on the crawler.current.
+func (crawler *Crawler) collect(ctx context.Context, n *html.Node) {
+	switch crawler.options.Action {
+
+	// sitemap, screenshot, seo, and a11y command collects links only from anchor tags
+	case A11Y:
+		fallthrough
+	case SEO:
+		fallthrough
+	case SITEMAP:
+		fallthrough
+	case SCREENSHOT:
+		// node is an anchor tag
+		if n.Type == html.ElementNode && n.Data == "a" {
+			for _, a := range n.Attr { // iterate tag attributes
+				if a.Key == "href" { // attribute is an href
+					crawler.store(a)
+					crawler.logger.Info(
+						"collected a page",
+						"tag", n.Data,
+						"attribute", a.Key,
+						"page", a.Val,
+					)
+					break // skip the remaining attributes
+				}
+			}
+		}
+
+	// test and perf commands collect links from anchor, link, img, and script tags
+	case PERF:
+		fallthrough
+	case TEST:
+		// report subresources and links over HTTP on an HTTPS page
+		crawler.secure(ctx, n)
+		// keep the images, scripts, and stylesheets the page loads to add up its weight
+		crawler.resource(n)
+		// node is an anchor tag or a link tag
+		if n.Type == html.ElementNode && (n.Data == "a" || n.Data == "link") {
+			for _, a := range n.Attr { // iterate tag attributes
+				if a.Key == "href" { // attribute is an href
+					crawler.store(a)
+					crawler.logger.Info(
+						"collected a page",
+						"tag", n.Data,
+						"attribute", a.Key,
+						"page", a.Val,
+					)
+					break // skip the remaining attributes
+				} else if a.Key == "data-href" { // attribute is a data-href
+					crawler.store(a)
+					crawler.logger.Info(
+						"collected a page",
+						"tag", n.Data,
+						"attribute", a.Key,
+						"page", a.Val,
+					)
+					break // skip the remaining attributes
+				}
+			}
+		}
+		// node is an img tag or script tag
+		if n.Type == html.ElementNode && (n.Data == "img" || n.Data == "script") {
+			for _, a := range n.Attr { // iterate tag attributes
+				if a.Key == "src" { // attribute is a src
+					crawler.store(a)
+					crawler.logger.Info(
+						"collected a page",
+						"tag", n.Data,
+						"attribute", a.Key,
+						"page", a.Val,
+					)
+					break // skip the remaining attributes
+				}
+			}
+		}
+	}
+
+	// visit each link on the current page
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		crawler.collect(ctx, c)
+	}
+}
+
+// The crawler will store a link in temporary storage as it crawls. The link is
+// resolved against the current page and requested as it is, while its normal form
+// is kept in visited so the variants of a URL are only requested once.
+func (crawler *Crawler) store(attr html.Attribute) {
+	if strings.HasPrefix(strings.TrimSpace(attr.Val), "#") { // link is on the same page
+		return
+	}
+	link, err := crawler.resolve(attr.Val)
+	if err != nil { // the link is skipped, as it cannot be requested
+		crawler.logger.Error("error parsing a link", "link", attr.Val, "error", err)
+		return
+	}
+	key := crawler.normalizer.Normalize(link)
+	if crawler.visited.Contains(key) { // the link was visited already
+		return
+	}
+	kind := External
+	if u, err := url.Parse(link); err == nil && crawler.internal(u) {
+		kind = Internal
+	}
+	(*crawler.visited)[key] = kind
+	crawler.current.links[link] = kind // add link to Set of links
+}
+
+// Returns link resolved against the URL the current page was served from, without
+// its fragment, or an error if link cannot be parsed.
+func (crawler *Crawler) resolve(link string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return "", err
+	}
+	base := crawler.current.request.URL
+	if crawler.current.response != nil && crawler.current.response.Request != nil {
+		base = crawler.current.response.Request.URL
+	}
+	u = base.ResolveReference(u)
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String(), nil
+}
+
+// Returns true if link is on the site of the root page, namely it has the same
+// scheme and host.
+func (crawler *Crawler) internal(link *url.URL) bool {
+	root := crawler.sitemap.Root().GetElement().request.URL
+	return strings.EqualFold(link.Scheme, root.Scheme) && strings.EqualFold(link.Host, root.Host)
+}
+
+// Performs an HTTP request to get the current page. An error is only returned if
+// ctx is done, otherwise the error is kept with the page and reported.
+func (crawler *Crawler) fetch(ctx context.Context) error {
+	// verify page URL contains valid URL
+	url, err := url.Parse(crawler.current.request.URL.String())
+	if err != nil || url.Scheme == "" || url.Host == "" {
+		crawler.logger.Info("invalid URL", "url", url)
+		return nil // skip the remaining code
+	}
+	// a link that matches an ignore rule is not requested
+	if rule := crawler.scope.Ignored(url.String()); rule != nil {
+		crawler.current.ignored = rule
+		crawler.logger.Info("ignored a page", "page", url.String(), "reason", rule.String())
+		return nil
+	}
+	// delay the http request, and wait long enough to keep to the rate
+	wait := crawler.options.Delay
+	if rate := crawler.options.Rate; rate > 0 {
+		wait = max(wait, time.Until(crawler.last.Add(time.Duration(float64(time.Second)/rate))))
+	}
+	delay := time.NewTimer(wait)
+	defer delay.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-delay.C:
+	}
+	crawler.last = time.Now()
+	// measure the phases of the request
+	meter := newMeter()
+	crawler.current.meter = meter
+	crawler.current.request = crawler.current.request.WithContext(httptrace.WithClientTrace(ctx, meter.trace()))
+	// inspect the certificate of each HTTPS host once
+	if url.Scheme == "https" && !crawler.inspected.Contains(url.Host) {
+		(*crawler.inspected)[url.Host] = 0
+		crawler.inspect(ctx, url)
+	}
+	// send credentials only to allowed hosts
+	crawler.auth.Apply(crawler.current.request)
+	// start timer to get request time
+	start := time.Now()
+	var cached bool
+	crawler.current.response, cached, err = crawler.cached(crawler.current)
+	crawler.current.requestTime = fmt.Sprintf("%d ms", time.Since(start).Milliseconds())
+	if cached {
+		crawler.current.requestTime += " (cached)"
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		crawler.logger.Error(
+			"error getting the page",
+			"page", crawler.current.request.URL.String(),
+			"error", err,
+		)
+		crawler.current.err = err
+		return nil
+	}
+	meter.wrap(crawler.current.response)
+	crawler.current.redirects = Chain(crawler.current.response)
+	crawler.logger.Info(
+		"fetched a page",
+		"page", crawler.current.request.URL.String(),
+		"status", crawler.current.response.Status,
+		"request time", crawler.current.requestTime,
+		"redirects", max(len(crawler.current.redirects)-1, 0),
+	)
+	return nil
+}
+
+// Inspects the certificate of the host of link and adds the problems with it to
+// the findings of the current page.
+func (crawler *Crawler) inspect(ctx context.Context, link *url.URL) {
+	cert, err := Inspect(ctx, link, crawler.roots, crawler.dial, crawler.options.DialTimeout)
+	if err != nil {
+		crawler.logger.Info("error inspecting the certificate", "host", link.Host, "error", err)
+		return
+	}
+	crawler.current.certificate = cert
+	crawler.current.findings = append(
+		crawler.current.findings,
+		cert.Findings(link.String(), crawler.options.CertDays, crawler.options.Insecure)...,
+	)
+	crawler.logger.Info(
+		"inspected a certificate",
+		"host", cert.Host,
+		"issuer", cert.Issuer,
+		"expires", cert.Expires.Format(time.DateOnly),
+		"version", cert.Version,
+	)
+}
+
+// Sends the request for page. An external page is only checked and not parsed, so
+// it is requested with HEAD. If the server does not support HEAD, the page is
+// requested with a GET for only its first byte.
+func (crawler *Crawler) do(page Page) (*http.Response, error) {
+	if page.kind == Internal {
+		return crawler.client.Do(page.request)
+	}
+	head := page.request.Clone(page.request.Context())
+	head.Method = http.MethodHead
+	res, err := crawler.client.Do(head)
+	if err != nil ||
+		(res.StatusCode != http.StatusMethodNotAllowed && res.StatusCode != http.StatusNotImplemented) {
+		return res, err
+	}
+	drain(res)
+	crawler.logger.Info("HEAD not supported, retrying with GET", "page", page.request.URL.String())
+	get := page.request.Clone(page.request.Context())
+	get.Header.Set("Range", "bytes=0-0")
+	res, err = crawler.client.Do(get)
+	if err != nil || res.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+		return res, err
+	}
+	// the server does not support the range, e.g. the page is empty
+	drain(res)
+	return crawler.client.Do(page.request)
+}
+
+// Reads what remains of the body of res, up to a limit, and closes it so the
+// connection can be reused.
+func drain(res *http.Response) {
+	if res == nil || res.Body == nil {
+		return
+	}
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+	res.Body.Close()
+}
+
+// Performs the action specified in the options of the crawler on the current page.
+func (crawler *Crawler) process(ctx context.Context) error {
+	if m := crawler.current.meter; m != nil {
+		crawler.current.timing, crawler.current.size = m.result(crawler.current.response)
+	}
+	switch crawler.options.Action {
+	case A11Y:
+		// audit the page in a browser before reporting it
+		if err := crawler.audit(ctx); err != nil {
+			return err
+		}
+		fallthrough
+	case PERF:
+		// measure the page, in a browser if the render option is set
+		if err := crawler.measure(ctx); err != nil {
+			return err
+		}
+		fallthrough
+	case SEO:
+		fallthrough
+	case TEST:
+		// report the result of testing the link
+		result := BROKEN
+		status := ""
+		if crawler.current.ignored != nil { // the page was not requested
+			result = IGNORED
+			status = "not checked"
+		} else if crawler.current.err != nil { // the page could not be requested
+			status = crawler.current.err.Error()
+		} else {
+			result = Classify(crawler.current.response)
+			status = crawler.current.response.Status
+			if result != OK && crawler.scope.Allowed(crawler.current.response.StatusCode) {
+				result = IGNORED
+			}
+		}
+		r := NewRecord(
+			crawler.current.request.URL.String(),
+			status,
+			result,
+			crawler.current.requestTime,
+			crawler.current.parentURL,
+		)
+		if crawler.current.meter != nil {
+			timing := crawler.current.timing
+			r.Timing = &timing
+			r.Size = crawler.current.size
+		}
+		r.Redirects = crawler.current.redirects
+		r.Findings = append(crawler.current.findings, run(crawler.checks, crawler.current)...)
+		r.Certificate = crawler.current.certificate
+		// errors that are expected do not fail the test
+		switch {
+		case crawler.current.ignored != nil:
+			r.Suppressed = crawler.current.ignored.String()
+		case result == IGNORED:
+			r.Suppressed = fmt.Sprintf("ignored status %d", crawler.current.response.StatusCode)
+		case crawler.known.Contains(r):
+			r.Suppressed = "known failure"
+		}
+		crawler.records = append(crawler.records, r)
+		crawler.handler.Checked(r)
+
+	case SCREENSHOT:
+		if crawler.current.err == nil && crawler.current.ignored == nil {
+			return crawler.screenshot(ctx)
+		}
+	}
+	return nil
+}
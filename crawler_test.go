@@ -0,0 +1,66 @@
+package linkt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// Crawls the site at link with options and returns the URLs of the records.
+func crawl(t *testing.T, options *Options, link string) (*Crawler, []string) {
+	t.Helper()
+	crawler, err := NewCrawler(options, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, _ := url.Parse(link)
+	if _, err := crawler.Crawl(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	urls := []string{}
+	for _, r := range crawler.Records() {
+		urls = append(urls, r.URL)
+	}
+	sort.Strings(urls)
+	return crawler, urls
+}
+
+func TestCrawlLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		page string
+		// paths of the links that are tested besides the root
+		want []string
+	}{
+		{"malformed href", `<a href="http://a b.com/">bad</a><a href="/a">a</a>`, []string{"/a"}},
+		{"variants of a link", `<a href="/a/">a</a><a href="/a/index.html">a</a><a href="/a?utm_source=x">a</a>`, []string{"/a/"}},
+		{"root", `<a href="/">home</a><a href="./">home</a><a href="#top">top</a>`, nil},
+		{"relative and absolute", `<a href="a">a</a><a href="{root}/a">a</a>`, []string{"/a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				if r.URL.Path == "/" {
+					w.Write([]byte(strings.ReplaceAll(tt.page, "{root}", server.URL)))
+				}
+			}))
+			defer server.Close()
+			_, got := crawl(t, NewOptions(TEST), server.URL)
+			want := []string{server.URL}
+			for _, p := range tt.want {
+				want = append(want, server.URL+p)
+			}
+			sort.Strings(want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("tested %v, want %v", got, want)
+			}
+		})
+	}
+}
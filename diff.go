@@ -0,0 +1,123 @@
+package linkt
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A link whose result changed between two tests of a site.
+type Change struct {
+	URL       string `json:"url"`
+	ParentURL string `json:"parentURL,omitempty"`
+	// status in each test, empty if the link was not in it
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+	// request time in milliseconds in each test, zero if it is unknown
+	BeforeTime float64 `json:"beforeTime,omitempty"`
+	AfterTime  float64 `json:"afterTime,omitempty"`
+}
+
+// The changes between the results of two tests of a site.
+type Diff struct {
+	// links that are broken and were not before
+	Broken []Change `json:"broken"`
+	// links that were broken and are not anymore
+	Fixed []Change `json:"fixed"`
+	// links whose status changed, without those that broke or were fixed
+	Changed []Change `json:"changed"`
+	// links whose request time grew by more than the threshold
+	Slower []Change `json:"slower"`
+	// links that are only in the new test, or only in the old one
+	Added   []Change `json:"added"`
+	Removed []Change `json:"removed"`
+}
+
+// Returns the changes from the records of an old test to the records of a new one.
+// A link is slower if its request time grew by more than slower. A link found more
+// than once is broken if any of its records is.
+func NewDiff(old []Record, new []Record, slower time.Duration) *Diff {
+	diff := &Diff{
+		Broken:  []Change{},
+		Fixed:   []Change{},
+		Changed: []Change{},
+		Slower:  []Change{},
+		Added:   []Change{},
+		Removed: []Change{},
+	}
+	before, beforeOrder := index(old)
+	after, afterOrder := index(new)
+	for _, link := range afterOrder {
+		a := after[link]
+		b, found := before[link]
+		at, timed := elapsed(a)
+		c := Change{URL: link, ParentURL: a.ParentURL, After: a.Status, AfterTime: at}
+		if !found {
+			diff.Added = append(diff.Added, c)
+			if a.Result == BROKEN {
+				diff.Broken = append(diff.Broken, c)
+			}
+			continue
+		}
+		bt, wasTimed := elapsed(b)
+		c.Before, c.BeforeTime = b.Status, bt
+		switch {
+		case a.Result == BROKEN && b.Result != BROKEN:
+			diff.Broken = append(diff.Broken, c)
+		case a.Result != BROKEN && b.Result == BROKEN:
+			diff.Fixed = append(diff.Fixed, c)
+		case a.Status != b.Status:
+			diff.Changed = append(diff.Changed, c)
+		}
+		if timed && wasTimed && at-bt > float64(slower.Microseconds())/1000 {
+			diff.Slower = append(diff.Slower, c)
+		}
+	}
+	for _, link := range beforeOrder {
+		if _, found := after[link]; !found {
+			b := before[link]
+			c := Change{URL: link, ParentURL: b.ParentURL, Before: b.Status}
+			c.BeforeTime, _ = elapsed(b)
+			diff.Removed = append(diff.Removed, c)
+		}
+	}
+	return diff
+}
+
+// Returns true if a link broke or got slower.
+func (d *Diff) Regressed() bool {
+	return len(d.Broken) > 0 || len(d.Slower) > 0
+}
+
+// Returns the record of each URL in records, and the URLs in the order they were
+// first found. A broken record takes the place of a record that is not.
+func index(records []Record) (map[string]Record, []string) {
+	byURL := map[string]Record{}
+	order := []string{}
+	for _, r := range records {
+		existing, found := byURL[r.URL]
+		if !found {
+			order = append(order, r.URL)
+		}
+		if !found || (r.Result == BROKEN && existing.Result != BROKEN) {
+			byURL[r.URL] = r
+		}
+	}
+	return byURL, order
+}
+
+// Returns the request time of r in milliseconds, and false if it is unknown or
+// the response was cached.
+func elapsed(r Record) (float64, bool) {
+	if strings.Contains(r.RequestTime, "cached") {
+		return 0, false
+	}
+	if r.Timing != nil && r.Timing.Total > 0 {
+		return r.Timing.Total, true
+	}
+	ms, err := strconv.ParseFloat(strings.TrimSuffix(r.RequestTime, " ms"), 64)
+	if err != nil {
+		return 0, false
+	}
+	return ms, true
+}
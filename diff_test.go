@@ -0,0 +1,136 @@
+package linkt
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewDiff(t *testing.T) {
+	ok := func(link string, status string, time string) Record {
+		return NewRecord(link, status, OK, time, "https://example.com")
+	}
+	broken := func(link string, status string) Record {
+		return NewRecord(link, status, BROKEN, "10 ms", "https://example.com")
+	}
+	tests := []struct {
+		name string
+		old  []Record
+		new  []Record
+		// URLs in each list of the diff: broken, fixed, changed, slower, added, removed
+		want [6][]string
+	}{
+		{
+			"unchanged",
+			[]Record{ok("https://example.com/a", "200 OK", "10 ms")},
+			[]Record{ok("https://example.com/a", "200 OK", "12 ms")},
+			[6][]string{},
+		},
+		{
+			"broke",
+			[]Record{ok("https://example.com/a", "200 OK", "10 ms")},
+			[]Record{broken("https://example.com/a", "404 Not Found")},
+			[6][]string{0: {"https://example.com/a"}},
+		},
+		{
+			"fixed",
+			[]Record{broken("https://example.com/a", "404 Not Found")},
+			[]Record{ok("https://example.com/a", "200 OK", "10 ms")},
+			[6][]string{1: {"https://example.com/a"}},
+		},
+		{
+			"status changed",
+			[]Record{broken("https://example.com/a", "404 Not Found")},
+			[]Record{broken("https://example.com/a", "410 Gone")},
+			[6][]string{2: {"https://example.com/a"}},
+		},
+		{
+			"slower",
+			[]Record{ok("https://example.com/a", "200 OK", "10 ms")},
+			[]Record{ok("https://example.com/a", "200 OK", "200 ms")},
+			[6][]string{3: {"https://example.com/a"}},
+		},
+		{
+			"cached is not slower",
+			[]Record{ok("https://example.com/a", "200 OK", "10 ms")},
+			[]Record{ok("https://example.com/a", "200 OK", "200 ms (cached)")},
+			[6][]string{},
+		},
+		{
+			"added and removed",
+			[]Record{ok("https://example.com/a", "200 OK", "10 ms")},
+			[]Record{ok("https://example.com/b", "200 OK", "10 ms"), broken("https://example.com/c", "404 Not Found")},
+			[6][]string{
+				0: {"https://example.com/c"},
+				4: {"https://example.com/b", "https://example.com/c"},
+				5: {"https://example.com/a"},
+			},
+		},
+		{
+			"broken on any page",
+			[]Record{ok("https://example.com/a", "200 OK", "10 ms")},
+			[]Record{ok("https://example.com/a", "200 OK", "10 ms"), broken("https://example.com/a", "404 Not Found")},
+			[6][]string{0: {"https://example.com/a"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDiff(tt.old, tt.new, 100*time.Millisecond)
+			for i, changes := range [][]Change{d.Broken, d.Fixed, d.Changed, d.Slower, d.Added, d.Removed} {
+				got := []string{}
+				for _, c := range changes {
+					got = append(got, c.URL)
+				}
+				want := tt.want[i]
+				if want == nil {
+					want = []string{}
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("list %d of the diff = %v, want %v", i, got, want)
+				}
+			}
+			if regressed := len(tt.want[0]) > 0 || len(tt.want[3]) > 0; d.Regressed() != regressed {
+				t.Errorf("Regressed() = %v, want %v", d.Regressed(), regressed)
+			}
+		})
+	}
+}
+
+func TestIndex(t *testing.T) {
+	records := []Record{
+		NewRecord("https://example.com/b", "200 OK", OK, "1 ms", ""),
+		NewRecord("https://example.com/a", "200 OK", OK, "1 ms", ""),
+		NewRecord("https://example.com/b", "404 Not Found", BROKEN, "1 ms", ""),
+		NewRecord("https://example.com/b", "200 OK", OK, "1 ms", ""),
+	}
+	byURL, order := index(records)
+	if want := []string{"https://example.com/b", "https://example.com/a"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+	if got := byURL["https://example.com/b"].Result; got != BROKEN {
+		t.Errorf("result of a link found broken once = %q, want %q", got, BROKEN)
+	}
+}
+
+func TestElapsed(t *testing.T) {
+	tests := []struct {
+		name   string
+		record Record
+		want   float64
+		timed  bool
+	}{
+		{"request time", Record{RequestTime: "12.5 ms"}, 12.5, true},
+		{"timing", Record{RequestTime: "12 ms", Timing: &Timing{Total: 20}}, 20, true},
+		{"zero timing", Record{RequestTime: "12 ms", Timing: &Timing{}}, 12, true},
+		{"cached", Record{RequestTime: "0 ms (cached)", Timing: &Timing{Total: 20}}, 0, false},
+		{"unknown", Record{RequestTime: ""}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, timed := elapsed(tt.record)
+			if got != tt.want || timed != tt.timed {
+				t.Errorf("elapsed() = %v, %v, want %v, %v", got, timed, tt.want, tt.timed)
+			}
+		})
+	}
+}
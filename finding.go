@@ -0,0 +1,33 @@
+package linkt
+
+// Kind of finding
+const REDIRECT_LOOP = "redirect-loop"
+const REDIRECT_CHAIN = "redirect-chain"
+const INSECURE_REDIRECT = "insecure-redirect"
+const INTERNAL_REDIRECT = "internal-redirect"
+
+// Severity of a finding
+const ERROR = "error"
+const WARNING = "warning"
+const NOTICE = "notice"
+
+// Represents a problem the crawler found with a link that is reported along with
+// the result of testing it.
+type Finding struct {
+	Kind string `json:"kind"`
+	// ERROR, WARNING, or NOTICE
+	Severity string `json:"severity"`
+	URL      string `json:"url"`
+	Message  string `json:"message"`
+	// CSS selector of the element the finding is about
+	Selector string `json:"selector,omitempty"`
+	// impact reported by the tool that found the problem, such as axe-core
+	Impact string `json:"impact,omitempty"`
+	// page that explains the problem and how to fix it
+	HelpURL string `json:"helpURL,omitempty"`
+}
+
+// Creates and returns a new finding.
+func NewFinding(kind string, severity string, url string, message string) Finding {
+	return Finding{Kind: kind, Severity: severity, URL: url, Message: message}
+}
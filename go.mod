@@ -2,11 +2,16 @@ module github.com/barreirokevin/linkt
 
 go 1.23.2
 
-require golang.org/x/net v0.39.0
+require (
+	github.com/BurntSushi/toml v1.4.0
+	github.com/chromedp/cdproto v0.0.0-20250403032234-65de8f5d025b
+	github.com/chromedp/chromedp v0.13.6
+	golang.org/x/image v0.26.0
+	golang.org/x/net v0.39.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
-	github.com/chromedp/cdproto v0.0.0-20250403032234-65de8f5d025b // indirect
-	github.com/chromedp/chromedp v0.13.6 // indirect
 	github.com/chromedp/sysutil v1.1.0 // indirect
 	github.com/go-json-experiment/json v0.0.0-20250211171154-1ae217ad3535 // indirect
 	github.com/gobwas/httphead v0.1.0 // indirect
@@ -0,0 +1,20 @@
+package linkt
+
+// Receives the events of a crawl. The methods are called from the goroutine that
+// runs the crawl, one at a time.
+type Handler interface {
+	// Called after a page is visited, even if the request for it failed.
+	Visited(page Page)
+	// Called for each new link found on a page, before it is visited.
+	Discovered(page Page)
+	// Called with the result of testing a link.
+	Checked(record Record)
+}
+
+// A handler that ignores every event. It can be embedded in a handler that only
+// needs some of the events.
+type NopHandler struct{}
+
+func (NopHandler) Visited(page Page)     {}
+func (NopHandler) Discovered(page Page)  {}
+func (NopHandler) Checked(record Record) {}
@@ -0,0 +1,77 @@
+package linkt
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// The User-Agent sent when none is specified. Many sites deny requests from
+// clients that do not look like a browser.
+const defaultUserAgent = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+
+// The headers the crawler sends with each request. A header for a specific host
+// overrides the header sent to every host.
+type Headers struct {
+	defaults http.Header
+	hosts    map[string]http.Header
+}
+
+// Returns the headers specified with the user-agent and host-header options along
+// with the browser-like headers sent by default. Each host header is in the form
+// <host>=<name>: <value>.
+func NewHeaders(options *Options) (*Headers, error) {
+	headers := &Headers{
+		defaults: http.Header{
+			"User-Agent":      {defaultUserAgent},
+			"Accept":          {"text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8"},
+			"Accept-Language": {"en-US,en;q=0.9"},
+		},
+		hosts: map[string]http.Header{},
+	}
+	if options.UserAgent != "" {
+		headers.defaults.Set("User-Agent", options.UserAgent)
+	}
+	for _, h := range options.HostHeaders {
+		host, header, found := strings.Cut(h, "=")
+		name, value, valid := strings.Cut(header, ":")
+		if !found || !valid || host == "" || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid host header %q, expected <host>=<name>: <value>", h)
+		}
+		host = strings.ToLower(strings.TrimSpace(host))
+		if headers.hosts[host] == nil {
+			headers.hosts[host] = http.Header{}
+		}
+		headers.hosts[host].Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	return headers, nil
+}
+
+// Returns a copy of req with the headers for its host. Headers already set on req
+// are not replaced.
+func (h *Headers) Apply(req *http.Request) *http.Request {
+	req = req.Clone(req.Context())
+	for name, values := range h.hosts[strings.ToLower(req.URL.Hostname())] {
+		if req.Header.Get(name) == "" {
+			req.Header[name] = values
+		}
+	}
+	for name, values := range h.defaults {
+		if req.Header.Get(name) == "" {
+			req.Header[name] = values
+		}
+	}
+	return req
+}
+
+// An HTTP transport that sends the headers with each request, including the
+// requests for redirects.
+type headerTransport struct {
+	base    http.RoundTripper
+	headers *Headers
+}
+
+// Sends req with the headers for its host.
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(t.headers.Apply(req))
+}
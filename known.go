@@ -0,0 +1,42 @@
+package linkt
+
+// The kinds of the errors found with each URL in a previous test, which are
+// suppressed when they are found again.
+type Known map[string]Set[string, int]
+
+// Returns the errors found in records, the results of a previous test.
+func NewKnown(records []Record) Known {
+	known := Known{}
+	for _, r := range records {
+		for _, f := range r.Findings {
+			if f.Severity != ERROR {
+				continue
+			}
+			if known[r.URL] == nil {
+				known[r.URL] = Set[string, int]{}
+			}
+			known[r.URL][f.Kind] = 0
+		}
+	}
+	return known
+}
+
+// Returns true if each error found with r was found with the same URL before, so
+// the record only has known failures.
+func (k Known) Contains(r Record) bool {
+	kinds, found := k[r.URL]
+	if !found {
+		return false
+	}
+	errors := 0
+	for _, f := range r.Findings {
+		if f.Severity != ERROR {
+			continue
+		}
+		if !kinds.Contains(f.Kind) {
+			return false
+		}
+		errors++
+	}
+	return errors > 0
+}
@@ -0,0 +1,37 @@
+package linkt
+
+import "testing"
+
+func TestKnownContains(t *testing.T) {
+	record := func(link string, findings ...Finding) Record {
+		r := NewRecord(link, "404 Not Found", BROKEN, "1 ms", "")
+		r.Findings = findings
+		return r
+	}
+	broken := NewFinding(BROKEN_LINK, ERROR, "", "the link is broken")
+	insecure := NewFinding(INSECURE_LINK, WARNING, "", "links over HTTP")
+	mixed := NewFinding(MIXED_CONTENT, ERROR, "", "loads a script over HTTP")
+	known := NewKnown([]Record{
+		record("https://example.com/a", broken, insecure),
+		record("https://example.com/b", insecure),
+	})
+	tests := []struct {
+		name   string
+		record Record
+		want   bool
+	}{
+		{"same error", record("https://example.com/a", broken), true},
+		{"same error and a warning", record("https://example.com/a", broken, NewFinding(MISSING_H1, WARNING, "", ""), insecure), true},
+		{"new error", record("https://example.com/a", broken, mixed), false},
+		{"no errors", record("https://example.com/a", insecure), false},
+		{"only warnings before", record("https://example.com/b", broken), false},
+		{"other url", record("https://example.com/c", broken), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := known.Contains(tt.record); got != tt.want {
+				t.Errorf("Contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
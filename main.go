@@ -1,6 +0,0 @@
-package main
-
-func main() {
-	app := NewApp()
-	app.Run()
-}
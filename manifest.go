@@ -0,0 +1,159 @@
+package linkt
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The maximum length of a directory or file name before it is shortened with a hash.
+const maxNameLength = 64
+
+// Names that cannot be used for a file on Windows.
+var reservedNames = Set[string, int]{
+	"con": 0, "prn": 0, "aux": 0, "nul": 0,
+	"com1": 0, "com2": 0, "com3": 0, "com4": 0, "com5": 0, "com6": 0, "com7": 0, "com8": 0, "com9": 0,
+	"lpt1": 0, "lpt2": 0, "lpt3": 0, "lpt4": 0, "lpt5": 0, "lpt6": 0, "lpt7": 0, "lpt8": 0, "lpt9": 0,
+}
+
+// Represents an entry in the manifest of screenshots.
+type Entry struct {
+	File      string    `json:"file"`
+	URL       string    `json:"url"`
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+	Viewport  string    `json:"viewport,omitempty"`
+	Width     int64     `json:"width,omitempty"`
+	Height    int64     `json:"height,omitempty"`
+}
+
+// Maps each screenshot file to the page it was taken of.
+type Manifest struct {
+	Entries []Entry `json:"screenshots"`
+}
+
+// Returns an empty manifest.
+func NewManifest() *Manifest {
+	return &Manifest{Entries: []Entry{}}
+}
+
+// Returns the path, relative to the screenshot directory, of the file for a
+// screenshot of link taken with viewport. The host and each segment of the path
+// of link become directories, and the last segment becomes the file name. The query
+// string is hashed into the file name, and a segment that had to be changed to be a
+// valid name, that could collide with another on a case-insensitive file system, or
+// that contains a separator used for the hashes or the viewport, gets a hash of the
+// original segment. The same link and viewport always return the
+// same path, and different links do not share a path.
+func Filename(link *url.URL, viewport string, ext string) string {
+	segments := []string{segment(link.Host)}
+	p := strings.Trim(link.EscapedPath(), "/")
+	if p != "" {
+		for _, s := range strings.Split(p, "/") {
+			if u, err := url.PathUnescape(s); err == nil && !strings.Contains(u, "/") {
+				s = u
+			}
+			segments = append(segments, segment(s))
+		}
+	}
+	if p == "" || strings.HasSuffix(link.Path, "/") {
+		segments = append(segments, "index")
+	}
+	base := segments[len(segments)-1]
+	if link.RawQuery != "" {
+		base = fmt.Sprintf("%s_%s", base, hash("?" + link.RawQuery)[:8])
+	}
+	if viewport != "" {
+		base = fmt.Sprintf("%s@%s", base, sanitize(viewport))
+	}
+	segments[len(segments)-1] = shorten(base)
+	return filepath.FromSlash(fmt.Sprintf("%s.%s", path.Join(segments...), ext))
+}
+
+// Adds an entry for a screenshot to the manifest.
+func (m *Manifest) Add(file string, link string, status string, v Viewport) {
+	m.Entries = append(m.Entries, Entry{
+		File:      filepath.ToSlash(file),
+		URL:       link,
+		Status:    status,
+		Timestamp: time.Now().UTC(),
+		Viewport:  v.name,
+		Width:     v.width,
+		Height:    v.height,
+	})
+}
+
+// Writes the manifest to a file named manifest.json in directory dir.
+func (m *Manifest) Write(dir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "manifest.json"), data, 0666)
+}
+
+// Returns the directory or file name for a segment of a URL. The name gets a hash of
+// the segment if it differs from the segment, contains uppercase letters or the _
+// and @ separators, or is the name used for the index of a directory.
+func segment(s string) string {
+	name := sanitize(s)
+	if name != s || name == "index" || strings.ToLower(name) != name || strings.ContainsAny(name, "_@") {
+		return shorten(fmt.Sprintf("%s_%s", name, hash(s)[:8]))
+	}
+	return name
+}
+
+// Returns name with the characters that are not allowed in a file name replaced.
+func sanitize(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r < 0x20 || r == 0x7f:
+			b.WriteRune('_')
+		case strings.ContainsRune(`<>:"/\|?*`, r):
+			b.WriteRune('_')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	s := strings.TrimRight(b.String(), ". ")
+	switch {
+	case s == "":
+		return "_"
+	case reservedNames.Contains(strings.ToLower(strings.Split(s, ".")[0])):
+		return "_" + s
+	}
+	return shorten(s)
+}
+
+// Returns name shortened with a hash of name if it is longer than maxNameLength.
+func shorten(name string) string {
+	if len(name) <= maxNameLength {
+		return name
+	}
+	h := hash(name)[:16]
+	cut := maxNameLength - len(h) - 1
+	// do not cut a multi-byte character in half
+	for cut > 0 && !utf8Start(name[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s_%s", name[:cut], h)
+}
+
+// Returns true if b is the first byte of a UTF-8 encoded character.
+func utf8Start(b byte) bool {
+	return b&0xc0 != 0x80
+}
+
+// Returns the hexadecimal SHA-1 hash of s.
+func hash(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
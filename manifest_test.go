@@ -0,0 +1,72 @@
+package linkt
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFilename(t *testing.T) {
+	tests := []struct {
+		link     string
+		viewport string
+		want     string
+	}{
+		{"https://example.com", "", "example.com/index.png"},
+		{"https://example.com/", "", "example.com/index.png"},
+		{"https://example.com/blog/post", "", "example.com/blog/post.png"},
+		{"https://example.com/blog/", "", "example.com/blog/index.png"},
+		{"https://example.com/blog/post", "mobile", "example.com/blog/post@mobile.png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			link, _ := url.Parse(tt.link)
+			if got := filepath.ToSlash(Filename(link, tt.viewport, PNG)); got != tt.want {
+				t.Errorf("Filename() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilenameUnique(t *testing.T) {
+	tests := []struct {
+		name  string
+		links [][2]string // URL and viewport
+	}{
+		{"trailing slash", [][2]string{{"https://example.com/a", ""}, {"https://example.com/a/", ""}}},
+		{"index segment", [][2]string{{"https://example.com/", ""}, {"https://example.com/index", ""}}},
+		{"query", [][2]string{{"https://example.com/a", ""}, {"https://example.com/a?b=1", ""}, {"https://example.com/a?b=2", ""}}},
+		{"query named like a segment", [][2]string{{"https://example.com/index", ""}, {"https://example.com/?index", ""}}},
+		{"case", [][2]string{{"https://example.com/a", ""}, {"https://example.com/A", ""}}},
+		{"escaped slash", [][2]string{{"https://example.com/a/b", ""}, {"https://example.com/a%2Fb", ""}}},
+		{"invalid characters", [][2]string{{"https://example.com/a:b", ""}, {"https://example.com/a_b", ""}, {"https://example.com/a%3Fb", ""}}},
+		{"reserved name", [][2]string{{"https://example.com/con", ""}, {"https://example.com/_con", ""}}},
+		{"viewport", [][2]string{{"https://example.com/a", ""}, {"https://example.com/a", "mobile"}, {"https://example.com/a@mobile", ""}}},
+		{"hash suffix", [][2]string{{"https://example.com/a?q", ""}, {"https://example.com/a_" + hash("?q")[:8], ""}}},
+		{"long segment", [][2]string{
+			{"https://example.com/" + strings.Repeat("a", 100), ""},
+			{"https://example.com/" + shorten(strings.Repeat("a", 100)), ""},
+			{"https://example.com/" + strings.Repeat("a", 101), ""},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := map[string][2]string{}
+			for _, l := range tt.links {
+				link, err := url.Parse(l[0])
+				if err != nil {
+					t.Fatal(err)
+				}
+				name := strings.ToLower(Filename(link, l[1], PNG))
+				if other, found := seen[name]; found {
+					t.Errorf("%v and %v share the path %s", other, l, name)
+				}
+				seen[name] = l
+				if again := strings.ToLower(Filename(link, l[1], PNG)); again != name {
+					t.Errorf("Filename() of %s = %q, then %q", l[0], name, again)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package linkt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Kind of finding
+const MIXED_CONTENT = "mixed-content"
+const INSECURE_LINK = "insecure-link"
+
+// The attribute of each tag that loads a subresource of a page.
+var subresources = map[string]string{
+	"script": "src",
+	"img":    "src",
+	"iframe": "src",
+	"frame":  "src",
+	"audio":  "src",
+	"video":  "src",
+	"source": "src",
+	"track":  "src",
+	"embed":  "src",
+	"object": "data",
+	"link":   "href",
+}
+
+// The rel values of a link tag that loads a subresource.
+var subresourceRels = Set[string, int]{
+	"stylesheet": 0, "icon": 0, "shortcut": 0, "apple-touch-icon": 0,
+	"preload": 0, "modulepreload": 0, "prefetch": 0, "manifest": 0,
+}
+
+// Adds a finding to the current page for each subresource that element n loads
+// over HTTP, and for a link to a page over HTTP. Nothing is reported unless the
+// current page was served over HTTPS.
+func (crawler *Crawler) secure(ctx context.Context, n *html.Node) {
+	if n.Type != html.ElementNode || crawler.current.response == nil ||
+		crawler.current.response.Request.URL.Scheme != "https" {
+		return
+	}
+	page := crawler.current.request.URL.String()
+
+	// an anchor to a page over HTTP is an insecure link
+	if n.Data == "a" {
+		if href := strings.TrimSpace(attr(n, "href")); insecure(href) {
+			crawler.current.findings = append(crawler.current.findings, NewFinding(
+				INSECURE_LINK,
+				WARNING,
+				page,
+				fmt.Sprintf("links to %s over HTTP, %s", href, crawler.upgrade(ctx, href)),
+			))
+		}
+		return
+	}
+
+	// a subresource over HTTP is mixed content
+	key, found := subresources[n.Data]
+	if !found {
+		return
+	}
+	if n.Data == "link" && !isSubresource(attr(n, "rel")) {
+		return
+	}
+	sources := []string{strings.TrimSpace(attr(n, key))}
+	for _, candidate := range strings.Split(attr(n, "srcset"), ",") {
+		if fields := strings.Fields(candidate); len(fields) > 0 {
+			sources = append(sources, fields[0])
+		}
+	}
+	for _, src := range sources {
+		if insecure(src) {
+			crawler.current.findings = append(crawler.current.findings, NewFinding(
+				MIXED_CONTENT,
+				ERROR,
+				page,
+				fmt.Sprintf("loads %s %s over HTTP", n.Data, src),
+			))
+		}
+	}
+}
+
+// Returns true if link is an absolute URL with the http scheme.
+func insecure(link string) bool {
+	return strings.HasPrefix(strings.ToLower(link), "http://")
+}
+
+// Returns true if one of the values of rel loads a subresource.
+func isSubresource(rel string) bool {
+	for _, r := range strings.Fields(strings.ToLower(rel)) {
+		if subresourceRels.Contains(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns advice on whether link can be replaced with its HTTPS version. The HTTPS
+// version of each link is checked once, with ctx rather than the context of the page
+// request so the probe is not timed along with the page.
+func (crawler *Crawler) upgrade(ctx context.Context, link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "the link is not a valid URL"
+	}
+	u.Scheme = "https"
+	if _, checked := crawler.upgrades[u.String()]; !checked {
+		crawler.upgrades[u.String()] = false
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, u.String(), nil)
+		if err == nil {
+			res, err := crawler.client.Do(req)
+			if err == nil {
+				drain(res)
+				crawler.upgrades[u.String()] = res.StatusCode < 400 ||
+					res.StatusCode == http.StatusMethodNotAllowed
+			}
+		}
+	}
+	if crawler.upgrades[u.String()] {
+		return fmt.Sprintf("link to %s instead", u.String())
+	}
+	return "no HTTPS version was found"
+}
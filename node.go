@@ -1,4 +1,4 @@
-package main
+package linkt
 
 // A node in a general tree.
 type Node[T any] struct {
@@ -0,0 +1,123 @@
+package linkt
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Query parameters that only track where a visitor came from, removed from each
+// link by default. A trailing * matches any suffix.
+var trackingParams = []string{
+	"utm_*", "gclid", "gclsrc", "dclid", "fbclid", "msclkid", "mc_cid", "mc_eid", "_ga", "_gl", "yclid",
+}
+
+// File names a server responds with for a directory, removed from each link by
+// default.
+var indexFiles = []string{"index.html", "index.htm", "index.php"}
+
+// Rewrites links so the variants of a URL are visited only once.
+type Normalizer struct {
+	// query parameters to remove, a trailing * matches any suffix
+	strip []string
+	// whether paths are compared regardless of case
+	lowercase bool
+	// file names removed from the end of a path
+	index []string
+}
+
+// Returns a normalizer that removes the query parameters and index files in
+// options, along with the default ones.
+func NewNormalizer(options *Options) *Normalizer {
+	return &Normalizer{
+		strip:     append(append([]string{}, trackingParams...), options.StripParams...),
+		lowercase: options.LowercasePaths,
+		index:     append(append([]string{}, indexFiles...), options.IndexFiles...),
+	}
+}
+
+// Returns link, a URL or a path on the site, in its normal form. The fragment,
+// tracking parameters, index file, dot segments, and trailing slash are removed,
+// the query is sorted, the scheme and host are lowercased and the default port is
+// removed, and percent-encoding is normalized. The path is lowercased if the
+// normalizer was created with the lowercase paths option. A link that cannot be
+// parsed is only trimmed.
+func (n *Normalizer) Normalize(link string) string {
+	link = strings.TrimSpace(link)
+	u, err := url.Parse(link)
+	if err != nil || u.Opaque != "" {
+		return strings.TrimSuffix(link, "/")
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Host != "" {
+		host, port := strings.ToLower(u.Hostname()), u.Port()
+		if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+			port = ""
+		}
+		if strings.Contains(host, ":") { // IPv6
+			host = "[" + host + "]"
+		}
+		if port != "" {
+			host += ":" + port
+		}
+		u.Host = host
+	}
+
+	// path
+	p := u.Path
+	if p != "" {
+		trailing := strings.HasSuffix(p, "/")
+		p = path.Clean(p)
+		if trailing && p != "/" {
+			p += "/"
+		}
+		for _, i := range n.index {
+			if strings.HasSuffix(p, "/"+i) {
+				p = strings.TrimSuffix(p, i)
+				break
+			}
+		}
+		if n.lowercase {
+			p = strings.ToLower(p)
+		}
+	}
+	u.Path = strings.TrimSuffix(p, "/")
+	// an escaped slash is not the same as a slash, so such a path keeps its encoding
+	if raw := strings.TrimSuffix(u.RawPath, "/"); strings.Contains(strings.ToLower(raw), "%2f") {
+		if n.lowercase {
+			raw = strings.ToLower(raw)
+		}
+		u.RawPath = strings.ReplaceAll(raw, "%2f", "%2F")
+	} else {
+		u.RawPath = ""
+	}
+
+	// query
+	if u.RawQuery != "" {
+		query, err := url.ParseQuery(u.RawQuery)
+		if err == nil {
+			for key := range query {
+				if n.stripped(key) {
+					query.Del(key)
+				}
+			}
+			u.RawQuery = query.Encode() // sorted by key
+		}
+	}
+	u.ForceQuery = false
+	return u.String()
+}
+
+// Returns true if the query parameter key is removed.
+func (n *Normalizer) stripped(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range n.strip {
+		s = strings.ToLower(s)
+		if prefix, ok := strings.CutSuffix(s, "*"); ok && strings.HasPrefix(key, prefix) || key == s {
+			return true
+		}
+	}
+	return false
+}
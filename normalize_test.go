@@ -0,0 +1,38 @@
+package linkt
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		link    string
+		want    string
+	}{
+		{"tracking params", Options{}, "https://example.com/a?utm_source=x&id=1&gclid=2", "https://example.com/a?id=1"},
+		{"custom param", Options{StripParams: []string{"ref"}}, "https://example.com/a?ref=x&id=1", "https://example.com/a?id=1"},
+		{"sorted query", Options{}, "https://example.com/a?b=2&a=1", "https://example.com/a?a=1&b=2"},
+		{"path case kept", Options{}, "https://example.com/About", "https://example.com/About"},
+		{"lowercase paths", Options{LowercasePaths: true}, "https://example.com/About", "https://example.com/about"},
+		{"index file", Options{}, "https://example.com/a/index.html", "https://example.com/a"},
+		{"custom index file", Options{IndexFiles: []string{"default.aspx"}}, "https://example.com/a/default.aspx", "https://example.com/a"},
+		{"index file name in a path", Options{}, "https://example.com/a/myindex.html", "https://example.com/a/myindex.html"},
+		{"fragment", Options{}, "https://example.com/a#top", "https://example.com/a"},
+		{"dot segments", Options{}, "https://example.com/a/./b/../c/", "https://example.com/a/c"},
+		{"trailing slash", Options{}, "https://example.com/a/", "https://example.com/a"},
+		{"root", Options{}, "https://example.com/", "https://example.com"},
+		{"default http port", Options{}, "http://Example.com:80/a", "http://example.com/a"},
+		{"default https port", Options{}, "HTTPS://example.com:443/a", "https://example.com/a"},
+		{"other port", Options{}, "https://example.com:8443/a", "https://example.com:8443/a"},
+		{"escaped slash", Options{}, "https://example.com/a%2fb/", "https://example.com/a%2Fb"},
+		{"escaped slash lowercased", Options{LowercasePaths: true}, "https://example.com/A%2Fb", "https://example.com/a%2Fb"},
+		{"unparsable", Options{}, " http://[::1/ ", "http://[::1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewNormalizer(&tt.options).Normalize(tt.link); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.link, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,36 +1,131 @@
-package main
+package linkt
 
-import "flag"
+import (
+	"log/slog"
+	"time"
+)
 
-// The values for the options available when executing linkt.
+// String for each action a crawler performs on the pages of a site
+const SITEMAP = "sitemap"
+const TEST = "test"
+const SCREENSHOT = "screenshot"
+const SEO = "seo"
+const A11Y = "a11y"
+const PERF = "perf"
+
+// The options that configure a crawler.
 type Options struct {
-	version   bool
-	debug     bool
-	links     bool
-	images    bool
-	xml       bool
-	print     bool
-	directory string
-	delay     int
-	json      bool
+	// what the crawler does with each page: SITEMAP, TEST, SCREENSHOT, SEO, A11Y, or PERF
+	Action string
+	// logs the work of the crawler, nothing is logged if nil
+	Logger *slog.Logger
+	// time to wait before each request
+	Delay time.Duration
+	// most requests sent per second, no limit if 0
+	Rate float64
+	// regular expressions matched against the URL of each internal page, only the
+	// pages that match an include pattern, if any, and no exclude pattern are crawled
+	Include []string
+	Exclude []string
+	// rules of the links that are not requested
+	Ignore []Ignore
+	// status codes, or classes of status codes like 5xx, not reported as broken
+	IgnoreStatus []string
+	// results of a previous test, an error found again on the same URL is suppressed
+	Known []Record
+
+	// directory to save screenshots to
+	Directory string
+	// viewport sizes in the form <width>x<height>
+	Viewports []string
+	// names of device presets to emulate
+	Devices []string
+	// JPEG, PNG, WEBP, or PDF
+	Format string
+	// quality of a JPEG or WEBP screenshot, from 0 to 100
+	Quality int
+	// FULL or VIEWPORT
+	Capture string
+	// directory of screenshots to compare against
+	Baseline string
+	// mismatch percentage above which a comparison fails
+	Threshold float64
+	// CSS selectors of elements to hide or cover before a screenshot
+	Hide []string
+	Mask []string
+	// scripts to run before a screenshot, in the form <pattern>=<path>
+	Scripts []string
+	// CSS selector to wait for before a screenshot, an accessibility audit, or
+	// measuring web vitals
+	WaitFor string
+	// path of axe-core to audit pages with, the vendored copy is used if empty
+	Axe string
+	// how similar the content of two pages must be to be reported, from 0 to 1
+	Similarity float64
+	// whether to load each page in a browser to measure its web vitals
+	Render bool
+	// limits on the metrics of a page in the form <metric>=<limit>
+	Budgets []string
+
+	// headers in the form <name>: <value> and cookies in the form <name>=<value>
+	Headers   []string
+	Cookies   []string
+	CookieJar string
+	// credentials in the form <user>:<password>
+	BasicAuth string
+	Bearer    string
+	// page with the login form, and the values to submit in the form <name>=<value>
+	Login       string
+	LoginFields []string
+	// other hosts to send the credentials to
+	AuthHosts []string
+	UserAgent string
+	// headers in the form <host>=<name>: <value> sent only to a host
+	HostHeaders  []string
+	MaxRedirects int
+	NoFollow     bool
+	Insecure     bool
+	CABundle     string
+	// certificates that expire within this many days are reported
+	CertDays int
+	// addresses to connect to in the form <host>:<port>:<address>
+	Resolve     []string
+	Proxy       string
+	Timeout     time.Duration
+	DialTimeout time.Duration
+	TLSTimeout  time.Duration
+	// directory to cache responses in
+	Cache    string
+	CacheTTL time.Duration
+	// query parameters to remove from each link, in addition to tracking parameters,
+	// a trailing * matches any suffix
+	StripParams []string
+	// whether paths are compared regardless of case
+	LowercasePaths bool
+	// file names to remove from the end of a path, in addition to index.html,
+	// index.htm, and index.php
+	IndexFiles []string
+	// file to save the progress of the crawl to, and whether to resume from it
+	State  string
+	Resume bool
 }
 
-// Creates and returns Options which contains the values specified.
-func NewOptions() *Options {
-	options := &Options{}
-	flag.BoolVar(&options.version, "version", false, "")
-	flag.BoolVar(&options.version, "v", false, "")
-	flag.BoolVar(&options.debug, "debug", false, "")
-	flag.BoolVar(&options.debug, "d", false, "")
-	flag.BoolVar(&options.links, "l", false, "")
-	flag.BoolVar(&options.links, "links", false, "")
-	flag.BoolVar(&options.images, "i", false, "")
-	flag.BoolVar(&options.images, "images", false, "")
-	flag.BoolVar(&options.xml, "xml", false, "")
-	flag.BoolVar(&options.json, "json", false, "")
-	flag.BoolVar(&options.print, "print", false, "")
-	flag.StringVar(&options.directory, "dir", "", "")
-	flag.IntVar(&options.delay, "delay", 0, "")
-	flag.Parse()
-	return options
+// Creates and returns Options with the default values for action.
+func NewOptions(action string) *Options {
+	return &Options{
+		Action:       action,
+		Format:       JPEG,
+		Quality:      90,
+		Capture:      FULL,
+		Threshold:    0.1,
+		Similarity:   0.9,
+		WaitFor:      "body",
+		Budgets:      []string{"ttfb=800ms", "lcp=2500ms", "cls=0.1", "tbt=200ms"},
+		MaxRedirects: 5,
+		CertDays:     30,
+		Timeout:      10 * time.Second,
+		DialTimeout:  30 * time.Second,
+		TLSTimeout:   10 * time.Second,
+		CacheTTL:     24 * time.Hour,
+	}
 }
@@ -1,8 +1,10 @@
-package main
+package linkt
 
 import (
 	"net/http"
 	"net/url"
+
+	"golang.org/x/net/html"
 )
 
 const ( // Type of page
@@ -24,6 +26,24 @@ type Page struct {
 	response    *http.Response
 	requestTime string
 	parentURL   string
+	// redirect chain that was followed to get this page
+	redirects []Hop
+	// problems found with this page
+	findings []Finding
+	// certificate of the host, if this is the first page requested from the host
+	certificate *Certificate
+	// error that occurred while requesting this page
+	err error
+	// parsed HTML of this page, if it is an internal HTML page
+	doc *html.Node
+	// time spent in each phase of the request, and bytes in the body of the response
+	timing Timing
+	size   int64
+	meter  *meter
+	// images, scripts, and stylesheets loaded by this page
+	resources []string
+	// rule this page matched, so it was not requested
+	ignored *Ignore
 }
 
 // Returns a new page.
@@ -32,8 +52,79 @@ func NewPage(link *url.URL) *Page {
 		request: &http.Request{
 			Method: http.MethodGet,
 			URL:    link,
+			Header: http.Header{},
 		},
 		links: Set[string, int]{},
 		kind:  Unknown,
 	}
 }
+
+// Returns the URL of the page.
+func (p Page) URL() *url.URL {
+	return p.request.URL
+}
+
+// Returns the URL of the page the link to this page was found on.
+func (p Page) ParentURL() string {
+	return p.parentURL
+}
+
+// Returns the kind of the page: Internal, External, or Unknown.
+func (p Page) Kind() int {
+	return p.kind
+}
+
+// Returns the response to the request for the page, or nil if the page was not
+// requested or the request failed. Its body is already closed.
+func (p Page) Response() *http.Response {
+	return p.response
+}
+
+// Returns the time it took to request the page.
+func (p Page) RequestTime() string {
+	return p.requestTime
+}
+
+// Returns the time spent in each phase of the request for the page.
+func (p Page) Timing() Timing {
+	return p.timing
+}
+
+// Returns the number of bytes in the body of the response.
+func (p Page) Size() int64 {
+	return p.size
+}
+
+// Returns the redirect chain that was followed to get the page.
+func (p Page) Redirects() []Hop {
+	return p.redirects
+}
+
+// Returns the problems found with the page.
+func (p Page) Findings() []Finding {
+	return p.findings
+}
+
+// Returns the certificate of the host, if this is the first page requested from
+// the host, otherwise nil.
+func (p Page) Certificate() *Certificate {
+	return p.certificate
+}
+
+// Returns the error that occurred while requesting the page, or nil.
+func (p Page) Err() error {
+	return p.err
+}
+
+// Returns the parsed HTML of the page, or nil if the page is not an internal HTML
+// page.
+func (p Page) Document() *html.Node {
+	return p.doc
+}
+
+// Returns the set of links found on the page, which are only collected from an
+// internal HTML page. Each link is a URL resolved against the page, and maps to
+// its kind.
+func (p Page) Links() Set[string, int] {
+	return p.links
+}
@@ -0,0 +1,418 @@
+package linkt
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"golang.org/x/net/html"
+)
+
+// Kind of finding reported when a page is over a performance budget
+const OVER_BUDGET = "over-budget"
+
+// String for each metric a performance budget can be set for
+const (
+	TTFB   = "ttfb"
+	TOTAL  = "total"
+	SIZE   = "size"
+	WEIGHT = "weight"
+	FCP    = "fcp"
+	LCP    = "lcp"
+	CLS    = "cls"
+	TBT    = "tbt"
+)
+
+// Records the web vitals of the page as they are reported by the browser. TBT, the
+// total blocking time of long tasks, stands in for INP since no one interacts with
+// the page.
+const observeVitals = `window.__linkt = {lcp: 0, cls: 0, tbt: 0};
+new PerformanceObserver(l => l.getEntries().forEach(e => __linkt.lcp = e.startTime))
+	.observe({type: "largest-contentful-paint", buffered: true});
+new PerformanceObserver(l => l.getEntries().forEach(e => { if (!e.hadRecentInput) __linkt.cls += e.value }))
+	.observe({type: "layout-shift", buffered: true});
+new PerformanceObserver(l => l.getEntries().forEach(e => __linkt.tbt += Math.max(0, e.duration - 50)))
+	.observe({type: "longtask", buffered: true});`
+
+// Returns the web vitals recorded on the page once it has settled for a second.
+const readVitals = `new Promise(r => setTimeout(() => {
+	const fcp = performance.getEntriesByName("first-contentful-paint")[0];
+	r({fcp: fcp ? fcp.startTime : 0, lcp: __linkt.lcp, cls: __linkt.cls, tbt: __linkt.tbt});
+}, 1000))`
+
+// Time spent in each phase of a request, in milliseconds. A phase that did not
+// happen, such as DNS for a reused connection, is 0. TTFB includes the redirects
+// that were followed to get the page.
+type Timing struct {
+	DNS      float64 `json:"dns"`
+	Connect  float64 `json:"connect"`
+	TLS      float64 `json:"tls"`
+	TTFB     float64 `json:"ttfb"`
+	Download float64 `json:"download"`
+	Total    float64 `json:"total"`
+}
+
+// Metrics measured by a browser that loaded the page, in milliseconds except for
+// CLS, which has no unit.
+type Vitals struct {
+	FCP float64 `json:"fcp"`
+	LCP float64 `json:"lcp"`
+	CLS float64 `json:"cls"`
+	TBT float64 `json:"tbt"`
+}
+
+// The performance of a page.
+type Metrics struct {
+	URL    string `json:"url"`
+	HTML   bool   `json:"html"`
+	Timing Timing `json:"timing"`
+	// bytes in the body of the response
+	Size int64 `json:"size"`
+	// bytes in the body of the page and its images, scripts, and stylesheets
+	Weight int64 `json:"weight,omitempty"`
+	// URLs of the images, scripts, and stylesheets of an HTML page
+	Resources []string  `json:"resources,omitempty"`
+	Vitals    *Vitals   `json:"vitals,omitempty"`
+	Findings  []Finding `json:"findings,omitempty"`
+}
+
+// Limits on the metrics of a page, mapped by metric.
+type Budgets map[string]float64
+
+// Returns budgets from specs in the form <metric>=<limit>. The limit of a time is
+// a duration such as 800ms or 2.5s, or milliseconds, and the limit of a size is a
+// number of bytes with an optional kb or mb suffix. A later spec for a metric
+// overrides an earlier one.
+func NewBudgets(specs []string) (Budgets, error) {
+	budgets := Budgets{}
+	for _, spec := range specs {
+		metric, value, ok := strings.Cut(spec, "=")
+		metric = strings.ToLower(strings.TrimSpace(metric))
+		value = strings.ToLower(strings.TrimSpace(value))
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%q is not in the form <metric>=<limit>", spec)
+		}
+		var limit float64
+		var err error
+		switch metric {
+		case TTFB, TOTAL, FCP, LCP, TBT:
+			limit, err = milliseconds(value)
+		case SIZE, WEIGHT:
+			limit, err = byteCount(value)
+		case CLS:
+			limit, err = strconv.ParseFloat(value, 64)
+		default:
+			return nil, fmt.Errorf("unknown metric %q in %q", metric, spec)
+		}
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid limit %q in %q", value, spec)
+		}
+		budgets[metric] = limit
+	}
+	return budgets, nil
+}
+
+// Returns a finding for each metric of m that is over its budget.
+func (b Budgets) Check(m Metrics) []Finding {
+	values := map[string]float64{
+		TTFB:   m.Timing.TTFB,
+		TOTAL:  m.Timing.Total,
+		SIZE:   float64(m.Size),
+		WEIGHT: float64(m.Weight),
+	}
+	if m.Vitals != nil {
+		values[FCP] = m.Vitals.FCP
+		values[LCP] = m.Vitals.LCP
+		values[CLS] = m.Vitals.CLS
+		values[TBT] = m.Vitals.TBT
+	}
+	findings := []Finding{}
+	for _, metric := range []string{TTFB, TOTAL, SIZE, WEIGHT, FCP, LCP, CLS, TBT} {
+		limit, ok := b[metric]
+		value, measured := values[metric]
+		if !ok || !measured || value <= limit {
+			continue
+		}
+		findings = append(findings, NewFinding(OVER_BUDGET, WARNING, m.URL, fmt.Sprintf(
+			"the %s is %s, over the budget of %s", metric, Format(metric, value), Format(metric, limit),
+		)))
+	}
+	return findings
+}
+
+// Returns value of metric formatted with its unit.
+func Format(metric string, value float64) string {
+	switch metric {
+	case SIZE, WEIGHT:
+		switch {
+		case value >= 1<<20:
+			return fmt.Sprintf("%.1f MB", value/(1<<20))
+		case value >= 1<<10:
+			return fmt.Sprintf("%.1f KB", value/(1<<10))
+		}
+		return fmt.Sprintf("%.0f B", value)
+	case CLS:
+		return fmt.Sprintf("%.3f", value)
+	}
+	return fmt.Sprintf("%.0f ms", value)
+}
+
+// Returns the milliseconds in value, a duration or a number of milliseconds.
+func milliseconds(value string) (float64, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return float64(d.Microseconds()) / 1000, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// Returns the bytes in value, a number with an optional kb or mb suffix.
+func byteCount(value string) (float64, error) {
+	unit := 1.0
+	switch {
+	case strings.HasSuffix(value, "kb"):
+		unit, value = 1<<10, strings.TrimSuffix(value, "kb")
+	case strings.HasSuffix(value, "mb"):
+		unit, value = 1<<20, strings.TrimSuffix(value, "mb")
+	case strings.HasSuffix(value, "b"):
+		value = strings.TrimSuffix(value, "b")
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	return n * unit, err
+}
+
+// Measures the phases of a request with httptrace, and the size of the body of the
+// response as it is read.
+type meter struct {
+	mu    sync.Mutex
+	start time.Time
+	// start of the phases in progress
+	dns, connect, tls time.Time
+	timing            Timing
+	// when the first byte of the response and the end of its body were received
+	firstByte time.Time
+	end       time.Time
+	size      int64
+	body      io.ReadCloser
+}
+
+// Returns a meter that starts measuring now.
+func newMeter() *meter {
+	return &meter{start: time.Now()}
+}
+
+// Returns the trace that records the phases of a request. The phases of each
+// redirect are added up.
+func (m *meter) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { m.begin(&m.dns) },
+		DNSDone:           func(httptrace.DNSDoneInfo) { m.finish(&m.dns, &m.timing.DNS) },
+		ConnectStart:      func(string, string) { m.begin(&m.connect) },
+		ConnectDone:       func(string, string, error) { m.finish(&m.connect, &m.timing.Connect) },
+		TLSHandshakeStart: func() { m.begin(&m.tls) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { m.finish(&m.tls, &m.timing.TLS) },
+		GotFirstResponseByte: func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			m.firstByte = time.Now()
+		},
+	}
+}
+
+// Marks the start of a phase.
+func (m *meter) begin(start *time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	*start = time.Now()
+}
+
+// Adds the time since the start of a phase to total.
+func (m *meter) finish(start *time.Time, total *float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !start.IsZero() {
+		*total += ms(time.Since(*start))
+		*start = time.Time{}
+	}
+}
+
+// Measures the body of res as it is read.
+func (m *meter) wrap(res *http.Response) {
+	if res == nil || res.Body == nil {
+		return
+	}
+	m.body = res.Body
+	res.Body = m
+}
+
+func (m *meter) Read(p []byte) (int, error) {
+	n, err := m.body.Read(p)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.size += int64(n)
+	m.end = time.Now()
+	return n, err
+}
+
+func (m *meter) Close() error {
+	return m.body.Close()
+}
+
+// Returns the timing of the request and the size of the body of res. The size is
+// the Content-Length of res if its body was not read to the end, as for a HEAD
+// request.
+func (m *meter) result(res *http.Response) (Timing, int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	timing := m.timing
+	end := m.end
+	if end.IsZero() {
+		end = time.Now()
+	}
+	if !m.firstByte.IsZero() {
+		timing.TTFB = ms(m.firstByte.Sub(m.start))
+		timing.Download = ms(max(end.Sub(m.firstByte), 0))
+	}
+	timing.Total = ms(end.Sub(m.start))
+	size := m.size
+	if res != nil && res.ContentLength > size {
+		size = res.ContentLength
+	}
+	return timing, size
+}
+
+// Returns d in milliseconds.
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// Adds the URL of the image, script, or stylesheet that n loads to the resources
+// of the current page.
+func (crawler *Crawler) resource(n *html.Node) {
+	if n.Type != html.ElementNode {
+		return
+	}
+	key := ""
+	switch n.Data {
+	case "img", "script":
+		key = "src"
+	case "link":
+		for _, rel := range strings.Fields(strings.ToLower(attr(n, "rel"))) {
+			if rel == "stylesheet" || rel == "icon" || rel == "preload" || rel == "modulepreload" {
+				key = "href"
+			}
+		}
+	}
+	link := strings.TrimSpace(attr(n, key))
+	if key == "" || link == "" || strings.HasPrefix(link, "#") {
+		return
+	}
+	if link, err := crawler.resolve(link); err == nil {
+		crawler.current.resources = append(crawler.current.resources, link)
+	}
+}
+
+// Records the metrics of the current page, and its web vitals if the render option
+// is set and it is an internal HTML page that loaded successfully.
+func (crawler *Crawler) measure(ctx context.Context) error {
+	page := crawler.current
+	if page.ignored != nil {
+		return nil
+	}
+	ok := page.response != nil && page.response.StatusCode >= 200 && page.response.StatusCode <= 299
+	m := Metrics{
+		URL:       page.request.URL.String(),
+		HTML:      page.doc != nil && ok,
+		Timing:    page.timing,
+		Size:      page.size,
+		Resources: page.resources,
+	}
+	if crawler.options.Render && m.HTML {
+		vitals, err := crawler.vitals(ctx, m.URL)
+		if err != nil {
+			return err
+		}
+		m.Vitals = vitals
+	}
+	crawler.metrics = append(crawler.metrics, m)
+	return nil
+}
+
+// Loads link in a browser and returns its web vitals. The browser is closed when
+// ctx is done.
+func (crawler *Crawler) vitals(ctx context.Context, link string) (*Vitals, error) {
+	options := crawler.options
+	ctx, cancel := NewBrowser(ctx, options)
+	defer cancel()
+	if err := chromedp.Run(ctx, crawler.auth.Browser(ctx)); err != nil {
+		return nil, fmt.Errorf("error sharing credentials with the browser for %s: %w", link, err)
+	}
+	vitals := &Vitals{}
+	err := chromedp.Run(ctx,
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			_, err := page.AddScriptToEvaluateOnNewDocument(observeVitals).Do(ctx)
+			return err
+		}),
+		navigate(link, options.Timeout),
+		chromedp.WaitVisible(options.WaitFor, chromedp.ByQuery),
+		chromedp.Evaluate(readVitals, vitals, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+			return p.WithAwaitPromise(true)
+		}),
+	)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("error measuring the web vitals of %s: %w", link, err)
+	}
+	crawler.logger.Info("measured a page", "page", link, "lcp", vitals.LCP, "cls", vitals.CLS)
+	return vitals, nil
+}
+
+// Returns the metrics of each HTML page that loaded successfully, with the weight
+// of its resources added up and the findings for the budgets it is over. A page
+// measured more than once is only returned the first time.
+func (crawler *Crawler) Performance() []Metrics {
+	sizes := map[string]int64{}
+	for _, m := range crawler.metrics {
+		sizes[m.URL] = m.Size
+	}
+	pages := []Metrics{}
+	seen := Set[string, int]{}
+	for _, m := range crawler.metrics {
+		if !m.HTML || seen.Contains(m.URL) {
+			continue
+		}
+		seen[m.URL] = 0
+		m.Weight = m.Size
+		counted := Set[string, int]{}
+		for _, r := range m.Resources {
+			if !counted.Contains(r) {
+				counted[r] = 0
+				m.Weight += sizes[r]
+			}
+		}
+		m.Findings = crawler.budgets.Check(m)
+		pages = append(pages, m)
+	}
+	return pages
+}
+
+// Returns the n pages that rank highest by value, from highest to lowest.
+func Rank(pages []Metrics, n int, value func(m Metrics) float64) []Metrics {
+	ranked := append([]Metrics{}, pages...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return value(ranked[i]) > value(ranked[j])
+	})
+	return ranked[:min(n, len(ranked))]
+}
@@ -0,0 +1,65 @@
+package linkt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewBudgets(t *testing.T) {
+	tests := []struct {
+		name  string
+		specs []string
+		want  Budgets
+		err   bool
+	}{
+		{"times", []string{"ttfb=800ms", "lcp=2.5s", "total=300"}, Budgets{TTFB: 800, LCP: 2500, TOTAL: 300}, false},
+		{"sizes", []string{"size=100kb", "weight=2mb", "Size = 512b"}, Budgets{SIZE: 512, WEIGHT: 2 << 20}, false},
+		{"cls", []string{"cls=0.1"}, Budgets{CLS: 0.1}, false},
+		{"later spec overrides", []string{"ttfb=800ms", "ttfb=1s"}, Budgets{TTFB: 1000}, false},
+		{"unknown metric", []string{"fid=100ms"}, nil, true},
+		{"no limit", []string{"ttfb="}, nil, true},
+		{"not a spec", []string{"ttfb"}, nil, true},
+		{"invalid limit", []string{"size=big"}, nil, true},
+		{"negative limit", []string{"tbt=-1"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewBudgets(tt.specs)
+			if (err != nil) != tt.err {
+				t.Fatalf("NewBudgets() error = %v, want an error: %v", err, tt.err)
+			}
+			if !tt.err && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewBudgets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBudgetsCheck(t *testing.T) {
+	budgets := Budgets{TTFB: 800, SIZE: 1000, WEIGHT: 5000, LCP: 2500, CLS: 0.1}
+	tests := []struct {
+		name    string
+		metrics Metrics
+		// number of metrics over budget
+		want int
+	}{
+		{"within", Metrics{Timing: Timing{TTFB: 800}, Size: 1000, Weight: 5000}, 0},
+		{"over", Metrics{Timing: Timing{TTFB: 801}, Size: 1001, Weight: 4000}, 2},
+		{"no budget", Metrics{Timing: Timing{Total: 99999}}, 0},
+		{"vitals", Metrics{Vitals: &Vitals{LCP: 3000, CLS: 0.2, TBT: 1000}}, 2},
+		{"vitals not measured", Metrics{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := budgets.Check(tt.metrics)
+			if len(findings) != tt.want {
+				t.Errorf("Check() = %v, want %d findings", findings, tt.want)
+			}
+			for _, f := range findings {
+				if f.Kind != OVER_BUDGET || f.Severity != WARNING {
+					t.Errorf("Check() returned %s %s, want %s %s", f.Kind, f.Severity, OVER_BUDGET, WARNING)
+				}
+			}
+		})
+	}
+}
@@ -1,19 +1,47 @@
-package main
+package linkt
 
 // Represents a record in the JSON file with test results.
 type Record struct {
 	URL         string `json:"url"`
 	Status      string `json:"status"`
+	Result      string `json:"result"`
 	RequestTime string `json:"requestTime"`
 	ParentURL   string `json:"parentURL"`
+	// time spent in each phase of the request, and bytes in the body of the response
+	Timing *Timing `json:"timing,omitempty"`
+	Size   int64   `json:"size,omitempty"`
+	// redirect chain that was followed to get the URL
+	Redirects []Hop `json:"redirects,omitempty"`
+	// problems found with the URL
+	Findings []Finding `json:"findings,omitempty"`
+	// certificate of the host, if the URL is the first one requested from the host
+	Certificate *Certificate `json:"certificate,omitempty"`
+	// why the errors found with the URL do not fail the test, e.g. it is a known
+	// failure
+	Suppressed string `json:"suppressed,omitempty"`
 }
 
 // Creates and returns a new record with test results.
-func NewRecord(url string, status string, requestTime string, parentURL string) Record {
+func NewRecord(url string, status string, result string, requestTime string, parentURL string) Record {
 	return Record{
 		URL:         url,
 		Status:      status,
+		Result:      result,
 		RequestTime: requestTime,
 		ParentURL:   parentURL,
 	}
 }
+
+// Returns true if the record has a finding with the severity ERROR that is not
+// suppressed.
+func (r Record) Failed() bool {
+	if r.Suppressed != "" {
+		return false
+	}
+	for _, f := range r.Findings {
+		if f.Severity == ERROR {
+			return true
+		}
+	}
+	return false
+}
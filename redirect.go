@@ -0,0 +1,92 @@
+package linkt
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// The number of redirects after which the crawler stops following a redirect chain.
+const maxFollow = 20
+
+// Represents a hop in a redirect chain.
+type Hop struct {
+	URL    string `json:"url"`
+	Status string `json:"status"`
+}
+
+// Returns the redirect chain that ended with res, including the final response.
+// The chain is empty if the request was not redirected.
+func Chain(res *http.Response) []Hop {
+	if res.Request == nil || res.Request.Response == nil {
+		return []Hop{}
+	}
+	hops := []Hop{{URL: res.Request.URL.String(), Status: res.Status}}
+	for r := res.Request.Response; r != nil; r = r.Request.Response {
+		hops = append([]Hop{{URL: r.Request.URL.String(), Status: r.Status}}, hops...)
+	}
+	return hops
+}
+
+// Returns the findings for the redirect chain of page. A chain is reported if it
+// loops, has more than max redirects, or goes from HTTPS to HTTP. An internal page
+// that redirects is reported so its link can be updated, unless the redirect only
+// adds a trailing slash.
+func Redirects(page Page, max int) []Finding {
+	findings := []Finding{}
+	res := page.response
+	link := page.request.URL.String()
+	hops := page.redirects
+
+	// a loop ends on a redirect to a URL that is already in the chain
+	if location, err := res.Location(); err == nil && res.StatusCode >= 300 && res.StatusCode <= 399 {
+		for _, h := range hops {
+			if h.URL == location.String() {
+				findings = append(findings, NewFinding(
+					REDIRECT_LOOP,
+					ERROR,
+					link,
+					fmt.Sprintf("redirects back to %s", location.String()),
+				))
+				break
+			}
+		}
+	}
+	if len(hops)-1 > max {
+		findings = append(findings, NewFinding(
+			REDIRECT_CHAIN,
+			WARNING,
+			link,
+			fmt.Sprintf("redirects %d times, more than the maximum of %d", len(hops)-1, max),
+		))
+	}
+	for i := 1; i < len(hops); i++ {
+		if strings.HasPrefix(hops[i-1].URL, "https://") && strings.HasPrefix(hops[i].URL, "http://") {
+			findings = append(findings, NewFinding(
+				INSECURE_REDIRECT,
+				ERROR,
+				link,
+				fmt.Sprintf("redirects from %s to %s over HTTP", hops[i-1].URL, hops[i].URL),
+			))
+		}
+	}
+	// the target is the end of the chain, or the location if redirects are not followed
+	target := ""
+	if len(hops) > 1 {
+		target = hops[len(hops)-1].URL
+	} else if location, err := res.Location(); err == nil {
+		target = location.String()
+	}
+	loop := len(findings) > 0 && findings[0].Kind == REDIRECT_LOOP
+	if page.kind == Internal && target != "" && !loop {
+		if strings.TrimSuffix(target, "/") != strings.TrimSuffix(link, "/") {
+			findings = append(findings, NewFinding(
+				INTERNAL_REDIRECT,
+				NOTICE,
+				link,
+				fmt.Sprintf("redirects to %s, link to it instead", target),
+			))
+		}
+	}
+	return findings
+}
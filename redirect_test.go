@@ -0,0 +1,78 @@
+package linkt
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch p := r.URL.Path; {
+		case p == "/":
+			w.Header().Set("Content-Type", "text/html")
+			for _, l := range []string{"/a", "/loop/1", "/hops/0", "/dir"} {
+				fmt.Fprintf(w, `<a href="%s">%s</a>`, l, l)
+			}
+		case p == "/a":
+			http.Redirect(w, r, "/b", http.StatusMovedPermanently)
+		case p == "/b":
+			http.Redirect(w, r, "/c", http.StatusFound)
+		case p == "/loop/1":
+			http.Redirect(w, r, "/loop/2", http.StatusFound)
+		case p == "/loop/2":
+			http.Redirect(w, r, "/loop/1", http.StatusFound)
+		case strings.HasPrefix(p, "/hops/"):
+			if n, _ := strconv.Atoi(strings.TrimPrefix(p, "/hops/")); n < 7 {
+				http.Redirect(w, r, fmt.Sprintf("/hops/%d", n+1), http.StatusFound)
+			}
+		case p == "/dir":
+			http.Redirect(w, r, "/dir/", http.StatusMovedPermanently)
+		}
+	}))
+	defer server.Close()
+	crawler, _ := crawl(t, NewOptions(TEST), server.URL)
+	records := map[string]Record{}
+	for _, r := range crawler.Records() {
+		records[strings.TrimPrefix(r.URL, server.URL)] = r
+	}
+
+	tests := []struct {
+		link string
+		// URLs of the hops in the chain
+		hops []string
+		// kinds of the findings
+		findings []string
+	}{
+		{"/a", []string{"/a", "/b", "/c"}, []string{INTERNAL_REDIRECT}},
+		{"/loop/1", []string{"/loop/1", "/loop/2"}, []string{REDIRECT_LOOP}},
+		{"/hops/0", []string{"/hops/0", "/hops/1", "/hops/2", "/hops/3", "/hops/4", "/hops/5", "/hops/6", "/hops/7"}, []string{REDIRECT_CHAIN, INTERNAL_REDIRECT}},
+		{"/dir", []string{"/dir", "/dir/"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			r, found := records[tt.link]
+			if !found {
+				t.Fatalf("no record for %s", tt.link)
+			}
+			hops := []string{}
+			for _, h := range r.Redirects {
+				hops = append(hops, strings.TrimPrefix(h.URL, server.URL))
+			}
+			if !reflect.DeepEqual(hops, tt.hops) {
+				t.Errorf("redirects = %v, want %v", hops, tt.hops)
+			}
+			findings := []string{}
+			for _, f := range r.Findings {
+				findings = append(findings, f.Kind)
+			}
+			if !reflect.DeepEqual(findings, tt.findings) {
+				t.Errorf("findings = %v, want %v", findings, tt.findings)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package linkt
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Result of testing a link
+const OK = "ok"
+const INFO = "info"
+const REDIRECT = "redirect"
+const BROKEN = "broken"
+const AMBIGUOUS = "ambiguous"
+const IGNORED = "ignored"
+
+// Response headers set by bot protection services when they block or challenge
+// a request, with the value that signals it, or empty if any value does.
+var botHeaders = map[string]string{
+	"Cf-Mitigated":      "challenge",
+	"X-Datadome":        "",
+	"X-Amzn-Waf-Action": "",
+	"X-Sucuri-Block":    "",
+	"X-Px-Block":        "",
+}
+
+// Returns the result of testing a link with response res. A response that looks like
+// the site is blocking bots, such as LinkedIn's 999 or a 403 from a bot protection
+// service, is ambiguous instead of broken, since the link may work in a browser.
+func Classify(res *http.Response) string {
+	switch status := res.StatusCode; {
+	case status == 999:
+		return AMBIGUOUS
+	case status == http.StatusTooManyRequests:
+		return AMBIGUOUS
+	case (status == http.StatusForbidden || status == http.StatusServiceUnavailable) && blocked(res):
+		return AMBIGUOUS
+	case status >= 100 && status <= 199:
+		return INFO
+	case status >= 200 && status <= 299:
+		return OK
+	case status >= 300 && status <= 399:
+		return REDIRECT
+	default:
+		return BROKEN
+	}
+}
+
+// Returns true if res has the signs of a challenge or block by a bot protection
+// service. The Server header of a CDN is not a sign, since the sites behind it
+// return real errors too.
+func blocked(res *http.Response) bool {
+	for h, signal := range botHeaders {
+		value := res.Header.Get(h)
+		if value != "" && (signal == "" || strings.EqualFold(value, signal)) {
+			return true
+		}
+	}
+	return false
+}
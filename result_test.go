@@ -0,0 +1,38 @@
+package linkt
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		headers map[string]string
+		want    string
+	}{
+		{"ok", 200, nil, OK},
+		{"redirect", 301, nil, REDIRECT},
+		{"not found", 404, nil, BROKEN},
+		{"linkedin", 999, nil, AMBIGUOUS},
+		{"rate limited", 429, nil, AMBIGUOUS},
+		{"cloudflare challenge", 403, map[string]string{"Cf-Mitigated": "challenge"}, AMBIGUOUS},
+		{"waf block", 403, map[string]string{"X-Amzn-Waf-Action": "block"}, AMBIGUOUS},
+		{"outage behind a cdn", 503, map[string]string{"Server": "cloudflare"}, BROKEN},
+		{"forbidden behind a cdn", 403, map[string]string{"Server": "AkamaiGHost"}, BROKEN},
+		{"cf-mitigated without a challenge", 403, map[string]string{"Cf-Mitigated": "other"}, BROKEN},
+		{"waf header on a 404", 404, map[string]string{"X-Datadome": "1"}, BROKEN},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			for k, v := range tt.headers {
+				res.Header.Set(k, v)
+			}
+			if got := Classify(res); got != tt.want {
+				t.Errorf("Classify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package linkt
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A pattern of links that are not checked, along with why they are not, e.g. the
+// site blocks bots, and the time it stops applying.
+type Ignore struct {
+	// regular expression matched against the URL of a link
+	Pattern string `json:"pattern"`
+	Reason  string `json:"reason,omitempty"`
+	// last day the rule applies on, zero if the rule does not expire
+	Expires time.Time `json:"expires"`
+}
+
+// Returns true if the rule stopped applying before now, namely now is after the day
+// it expires on in the time zone of now.
+func (i Ignore) Expired(now time.Time) bool {
+	if i.Expires.IsZero() {
+		return false
+	}
+	y, m, d := i.Expires.Date()
+	return !now.Before(time.Date(y, m, d+1, 0, 0, 0, 0, now.Location()))
+}
+
+// Returns why a link that matches the rule is not checked.
+func (i Ignore) String() string {
+	if i.Reason == "" {
+		return fmt.Sprintf("ignored by %s", i.Pattern)
+	}
+	return fmt.Sprintf("%s (%s)", i.Reason, i.Pattern)
+}
+
+// An ignore rule with its compiled pattern.
+type ignoreRule struct {
+	Ignore
+	pattern *regexp.Regexp
+}
+
+// Decides which links a crawler requests, which pages it crawls, and which status
+// codes it does not report as broken.
+type Scope struct {
+	// patterns of the internal pages that are crawled, every page if empty
+	include []*regexp.Regexp
+	// patterns of the internal pages that are not crawled
+	exclude []*regexp.Regexp
+	// rules of the links that are not requested, without the ones that expired
+	ignore []ignoreRule
+	// status codes, and classes of status codes like 5xx, that are not broken
+	statuses Set[string, int]
+}
+
+// Returns the scope specified with the include, exclude, ignore, and ignore status
+// options. The patterns are regular expressions matched against the URL of a link,
+// and a status is a code like 429 or a class like 5xx. An ignore rule that expired
+// is not applied.
+func NewScope(options *Options) (*Scope, error) {
+	scope := &Scope{statuses: Set[string, int]{}}
+	var err error
+	if scope.include, err = patterns(options.Include); err != nil {
+		return nil, fmt.Errorf("invalid include pattern: %w", err)
+	}
+	if scope.exclude, err = patterns(options.Exclude); err != nil {
+		return nil, fmt.Errorf("invalid exclude pattern: %w", err)
+	}
+	now := time.Now()
+	for _, i := range options.Ignore {
+		p, err := regexp.Compile(i.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore pattern: %q: %w", i.Pattern, err)
+		}
+		if !i.Expired(now) {
+			scope.ignore = append(scope.ignore, ignoreRule{Ignore: i, pattern: p})
+		}
+	}
+	for _, s := range options.IgnoreStatus {
+		s = strings.ToLower(strings.TrimSpace(s))
+		class, isClass := strings.CutSuffix(s, "xx")
+		code, err := strconv.Atoi(class)
+		switch {
+		case err != nil, isClass && (code < 1 || code > 5), !isClass && (code < 100 || code > 999):
+			return nil, fmt.Errorf("invalid status %q, expected a code like 429 or a class like 5xx", s)
+		}
+		scope.statuses[s] = 0
+	}
+	return scope, nil
+}
+
+// Returns true if the internal page at link is crawled, namely if it matches an
+// include pattern, or there are none, and no exclude pattern.
+func (s *Scope) Crawled(link string) bool {
+	if len(s.include) > 0 && !matches(s.include, link) {
+		return false
+	}
+	return !matches(s.exclude, link)
+}
+
+// Returns the first ignore rule that link matches, so it is not requested, or nil
+// if there is none.
+func (s *Scope) Ignored(link string) *Ignore {
+	for _, r := range s.ignore {
+		if r.pattern.MatchString(link) {
+			return &r.Ignore
+		}
+	}
+	return nil
+}
+
+// Returns true if a response with the status code is not reported as broken.
+func (s *Scope) Allowed(code int) bool {
+	return s.statuses.Contains(strconv.Itoa(code)) || s.statuses.Contains(fmt.Sprintf("%dxx", code/100))
+}
+
+// Returns the regular expressions in specs.
+func patterns(specs []string) ([]*regexp.Regexp, error) {
+	compiled := []*regexp.Regexp{}
+	for _, spec := range specs {
+		p, err := regexp.Compile(spec)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", spec, err)
+		}
+		compiled = append(compiled, p)
+	}
+	return compiled, nil
+}
+
+// Returns true if link matches any of the patterns.
+func matches(patterns []*regexp.Regexp, link string) bool {
+	for _, p := range patterns {
+		if p.MatchString(link) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package linkt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIgnoreExpired(t *testing.T) {
+	day := func(s string) time.Time {
+		d, _ := time.ParseInLocation(time.DateTime, s, time.Local)
+		return d
+	}
+	expires := day("2027-06-30 00:00:00")
+	tests := []struct {
+		name    string
+		expires time.Time
+		now     time.Time
+		want    bool
+	}{
+		{"no expiry", time.Time{}, day("2030-01-01 00:00:00"), false},
+		{"day before", expires, day("2027-06-29 23:59:59"), false},
+		{"start of the day", expires, day("2027-06-30 00:00:00"), false},
+		{"end of the day", expires, day("2027-06-30 23:59:59"), false},
+		{"day after", expires, day("2027-07-01 00:00:00"), true},
+		{"date in utc", time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC), day("2027-06-30 23:59:59"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Ignore{Pattern: "x", Expires: tt.expires}).Expired(tt.now); got != tt.want {
+				t.Errorf("Expired(%s) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
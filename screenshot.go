@@ -0,0 +1,281 @@
+package linkt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"github.com/chromedp/chromedp/device"
+)
+
+// Output format of a screenshot
+const JPEG = "jpeg"
+const PNG = "png"
+const WEBP = "webp"
+const PDF = "pdf"
+
+// Area of a page captured by a screenshot
+const FULL = "full"
+const VIEWPORT = "viewport"
+
+// Device presets that can be emulated when taking screenshots.
+var devices = map[string]device.Info{
+	"iphone":           device.IPhone13.Device(),
+	"iphone-landscape": device.IPhone13landscape.Device(),
+	"iphone-se":        device.IPhoneSE.Device(),
+	"pixel":            device.Pixel5.Device(),
+	"pixel-landscape":  device.Pixel5landscape.Device(),
+	"galaxy":           device.GalaxyS9.Device(),
+	"tablet":           device.IPadgen7.Device(),
+	"tablet-landscape": device.IPadgen7landscape.Device(),
+	"ipad-pro":         device.IPadPro11.Device(),
+	"galaxy-tab":       device.GalaxyTabS4.Device(),
+}
+
+// The size of the browser window, or the device that is emulated, when taking
+// a screenshot of a page.
+type Viewport struct {
+	name   string
+	width  int64
+	height int64
+	device *device.Info
+}
+
+// Returns the viewports specified with the viewport and device options. Each
+// size is in the form <width>x<height>, e.g. 1280x800, and each device is the name
+// of a preset. If no viewport or device is specified, then a single viewport with
+// the browser's default size is returned.
+func NewViewports(sizes []string, presets []string) ([]Viewport, error) {
+	viewports := []Viewport{}
+	for _, s := range sizes {
+		w, h, found := strings.Cut(strings.ToLower(s), "x")
+		width, errW := strconv.ParseInt(w, 10, 64)
+		height, errH := strconv.ParseInt(h, 10, 64)
+		if !found || errW != nil || errH != nil || width <= 0 || height <= 0 {
+			return nil, fmt.Errorf("invalid viewport %q, expected <width>x<height>", s)
+		}
+		viewports = append(viewports, Viewport{name: s, width: width, height: height})
+	}
+	for _, p := range presets {
+		d, found := devices[strings.ToLower(p)]
+		if !found {
+			return nil, fmt.Errorf("unknown device %q", p)
+		}
+		viewports = append(viewports, Viewport{
+			name:   strings.ToLower(p),
+			width:  d.Width,
+			height: d.Height,
+			device: &d,
+		})
+	}
+	if len(viewports) == 0 {
+		viewports = append(viewports, Viewport{})
+	}
+	return viewports, nil
+}
+
+// Returns the action that makes the browser emulate the viewport. A viewport with
+// the browser's default size does not emulate anything.
+func (v Viewport) emulate() chromedp.Action {
+	switch {
+	case v.device != nil:
+		return chromedp.Emulate(v.device)
+	case v.width > 0 && v.height > 0:
+		return chromedp.EmulateViewport(v.width, v.height)
+	default:
+		return chromedp.ActionFunc(func(ctx context.Context) error { return nil })
+	}
+}
+
+// JavaScript that hides and masks the elements matching the selectors passed as
+// its arguments. A masked element is covered with a solid box.
+const conceal = `(function(hide, mask) {
+	for (const s of hide) {
+		document.querySelectorAll(s).forEach(e => e.style.setProperty("visibility", "hidden", "important"));
+	}
+	for (const s of mask) {
+		document.querySelectorAll(s).forEach(e => {
+			const r = e.getBoundingClientRect();
+			const box = document.createElement("div");
+			box.style.cssText = "position:absolute;z-index:2147483647;background:#ff00ff;" +
+				"left:" + (r.left + window.scrollX) + "px;top:" + (r.top + window.scrollY) + "px;" +
+				"width:" + r.width + "px;height:" + r.height + "px";
+			document.body.appendChild(box);
+		});
+	}
+})(%s, %s)`
+
+// A script that runs on each page whose URL matches a pattern before the page is
+// captured, e.g. to dismiss a modal or to log in.
+type Script struct {
+	pattern *regexp.Regexp
+	source  string
+}
+
+// Returns the scripts specified with the script option. Each script is in the form
+// <pattern>=<path>, where pattern is a regular expression matched against the URL
+// of a page and path is a JavaScript file.
+func NewScripts(specs []string) ([]Script, error) {
+	scripts := []Script{}
+	for _, spec := range specs {
+		i := strings.LastIndex(spec, "=")
+		if i < 1 {
+			return nil, fmt.Errorf("invalid script %q, expected <pattern>=<path>", spec)
+		}
+		pattern, err := regexp.Compile(spec[:i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid script pattern %q: %w", spec[:i], err)
+		}
+		source, err := os.ReadFile(spec[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("error reading script: %w", err)
+		}
+		scripts = append(scripts, Script{pattern: pattern, source: string(source)})
+	}
+	return scripts, nil
+}
+
+// Returns the actions that prepare the page at link for a screenshot. The scripts
+// that match link are run, the page waits for the selector specified, and then the
+// elements to hide and mask are concealed.
+func (crawler *Crawler) prepare(link string) chromedp.Tasks {
+	options := crawler.options
+	tasks := chromedp.Tasks{}
+	for _, s := range crawler.scripts {
+		if s.pattern.MatchString(link) {
+			tasks = append(tasks, chromedp.Evaluate(s.source, nil,
+				func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+					return p.WithAwaitPromise(true)
+				},
+			))
+		}
+	}
+	tasks = append(tasks, chromedp.WaitVisible(options.WaitFor, chromedp.ByQuery))
+	if len(options.Hide) > 0 || len(options.Mask) > 0 {
+		hide, _ := json.Marshal([]string(options.Hide))
+		mask, _ := json.Marshal([]string(options.Mask))
+		tasks = append(tasks, chromedp.Evaluate(fmt.Sprintf(conceal, hide, mask), nil))
+	}
+	return tasks
+}
+
+// Returns an error if the options for taking screenshots are not supported.
+func ValidateScreenshot(options *Options) error {
+	switch options.Format {
+	case JPEG, PNG, WEBP, PDF:
+	default:
+		return fmt.Errorf("unsupported format %q", options.Format)
+	}
+	switch options.Capture {
+	case FULL, VIEWPORT:
+	default:
+		return fmt.Errorf("unsupported capture %q", options.Capture)
+	}
+	if options.Quality < 0 || options.Quality > 100 {
+		return fmt.Errorf("quality %d is not between 0 and 100", options.Quality)
+	}
+	if options.Baseline != "" && options.Format == PDF {
+		return errors.New("a pdf cannot be compared against a baseline")
+	}
+	if strings.TrimSpace(options.WaitFor) == "" {
+		return errors.New("the selector to wait for is empty")
+	}
+	if options.Threshold < 0 || options.Threshold > 100 {
+		return fmt.Errorf("threshold %.2f is not between 0 and 100", options.Threshold)
+	}
+	return nil
+}
+
+// Returns the action that captures the page with format. Full-page capture
+// includes the content beyond the viewport. A PDF always contains the full page.
+func capture(format string, quality int, full bool, res *[]byte) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		if format == PDF {
+			*res, _, err = page.PrintToPDF().WithPrintBackground(true).Do(ctx)
+			return err
+		}
+		params := page.CaptureScreenshot().
+			WithFormat(page.CaptureScreenshotFormat(format)).
+			WithCaptureBeyondViewport(full).
+			WithFromSurface(true)
+		if format != PNG { // png is lossless so it does not have a quality
+			params = params.WithQuality(int64(quality))
+		}
+		*res, err = params.Do(ctx)
+		return err
+	})
+}
+
+// Takes a screenshot of the current page for each viewport and saves them to the
+// directory specified. The browser is closed when ctx is done.
+func (crawler *Crawler) screenshot(ctx context.Context) error {
+	options := crawler.options
+	link := crawler.current.request.URL.String()
+	// use the same browser for all viewports
+	ctx, cancel := NewBrowser(ctx, options)
+	defer cancel()
+	// share the credentials with the browser
+	if err := chromedp.Run(ctx, crawler.auth.Browser(ctx)); err != nil {
+		return fmt.Errorf("error sharing credentials with the browser for %s: %w", link, err)
+	}
+	for _, v := range crawler.viewports {
+		// name the screenshot file
+		filename := Filename(crawler.current.request.URL, v.name, options.Format)
+		path := filepath.Join(options.Directory, filename)
+		var buf []byte
+		err := chromedp.Run(ctx,
+			v.emulate(),
+			navigate(link, options.Timeout),
+			// Wait until page is ready to be captured
+			crawler.prepare(link),
+			// Take a screenshot of the page
+			capture(options.Format, options.Quality, options.Capture == FULL, &buf),
+		)
+		if err != nil {
+			return fmt.Errorf("error taking a screenshot of %s with viewport %q: %w", link, v.name, err)
+		}
+		// write the screenshot to file
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, buf, 0666); err != nil {
+			crawler.logger.Error(
+				"error writing data to image file",
+				"error", err,
+				"filename", filename,
+			)
+			continue
+		}
+		status := ""
+		if crawler.current.response != nil {
+			status = crawler.current.response.Status
+		}
+		crawler.manifest.Add(filename, link, status, v)
+		// compare the screenshot against the baseline
+		if options.Baseline != "" {
+			c, err := Compare(options.Baseline, options.Directory, filename, options.Threshold)
+			if err != nil {
+				return fmt.Errorf("error comparing %s against the baseline: %w", filename, err)
+			}
+			c.URL = link
+			c.Viewport = v.name
+			crawler.logger.Info(
+				"compared a screenshot",
+				"filename", filename,
+				"mismatch", fmt.Sprintf("%.2f%%", c.Mismatch),
+			)
+			crawler.comparisons = append(crawler.comparisons, c)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,297 @@
+package linkt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// Kind of finding reported by the SEO audit
+const MISSING_TITLE = "missing-title"
+const DUPLICATE_TITLE = "duplicate-title"
+const TITLE_LENGTH = "title-length"
+const MISSING_DESCRIPTION = "missing-description"
+const DUPLICATE_DESCRIPTION = "duplicate-description"
+const DESCRIPTION_LENGTH = "description-length"
+const MISSING_H1 = "missing-h1"
+const MULTIPLE_H1 = "multiple-h1"
+const MISSING_CANONICAL = "missing-canonical"
+const ROBOTS_CONFLICT = "robots-conflict"
+const MISSING_LANG = "missing-lang"
+const MISSING_ALT = "missing-alt"
+
+// The recommended length of a title and a meta description, in characters.
+const (
+	minTitle       = 30
+	maxTitle       = 60
+	minDescription = 70
+	maxDescription = 160
+)
+
+// Robots directives that contradict each other.
+var robotsConflicts = [][2]string{{"index", "noindex"}, {"follow", "nofollow"}}
+
+// A title or meta description shared by several pages.
+type Duplicate struct {
+	Kind  string   `json:"kind"`
+	Value string   `json:"value"`
+	URLs  []string `json:"urls"`
+}
+
+// Audits each internal HTML page for the problems that affect how it is indexed
+// and shown by search engines. The titles and descriptions of the pages are kept
+// so duplicates can be grouped across pages.
+type seoCheck struct {
+	// pages with each title and description, valued by the order they were found in
+	titles       map[string]Set[string, int]
+	descriptions map[string]Set[string, int]
+}
+
+// Returns an SEO check that has not seen any page.
+func newSEOCheck() *seoCheck {
+	return &seoCheck{titles: map[string]Set[string, int]{}, descriptions: map[string]Set[string, int]{}}
+}
+
+func (c *seoCheck) Name() string {
+	return "seo"
+}
+
+func (c *seoCheck) Check(page Page) []Finding {
+	doc := page.Document()
+	if doc == nil || page.response == nil || page.response.StatusCode < 200 || page.response.StatusCode > 299 {
+		return nil
+	}
+	link := page.URL().String()
+	findings := []Finding{}
+
+	// title
+	title := ""
+	if n := find(doc, "title"); n != nil {
+		title = collapse(text(n))
+	}
+	if title == "" {
+		findings = append(findings, NewFinding(MISSING_TITLE, ERROR, link, "the page has no title"))
+	} else {
+		add(c.titles, title, link)
+		if f, ok := length(TITLE_LENGTH, link, "title", title, minTitle, maxTitle); ok {
+			findings = append(findings, f)
+		}
+	}
+
+	// meta description and robots directives
+	description := ""
+	robots := []string{}
+	for _, n := range elements(doc, "meta") {
+		switch strings.ToLower(attr(n, "name")) {
+		case "description":
+			description = collapse(attr(n, "content"))
+		case "robots":
+			robots = append(robots, directives(attr(n, "content"))...)
+		}
+	}
+	if description == "" {
+		findings = append(findings, NewFinding(MISSING_DESCRIPTION, WARNING, link, "the page has no meta description"))
+	} else {
+		add(c.descriptions, description, link)
+		if f, ok := length(DESCRIPTION_LENGTH, link, "meta description", description, minDescription, maxDescription); ok {
+			findings = append(findings, f)
+		}
+	}
+	header := []string{}
+	for _, v := range page.response.Header.Values("X-Robots-Tag") {
+		header = append(header, directives(v)...)
+	}
+	if conflict := conflicting(append(robots, header...)); conflict != "" {
+		findings = append(findings, NewFinding(
+			ROBOTS_CONFLICT,
+			ERROR,
+			link,
+			fmt.Sprintf("the robots meta tag and X-Robots-Tag header contain %s", conflict),
+		))
+	}
+
+	// headings
+	switch h1 := len(elements(doc, "h1")); {
+	case h1 == 0:
+		findings = append(findings, NewFinding(MISSING_H1, WARNING, link, "the page has no h1"))
+	case h1 > 1:
+		findings = append(findings, NewFinding(MULTIPLE_H1, NOTICE, link, fmt.Sprintf("the page has %d h1s", h1)))
+	}
+
+	// canonical link
+	canonical := false
+	for _, n := range elements(doc, "link") {
+		for _, rel := range strings.Fields(strings.ToLower(attr(n, "rel"))) {
+			canonical = canonical || (rel == "canonical" && attr(n, "href") != "")
+		}
+	}
+	if !canonical {
+		findings = append(findings, NewFinding(MISSING_CANONICAL, WARNING, link, "the page has no canonical link"))
+	}
+
+	// language
+	if n := find(doc, "html"); n == nil || strings.TrimSpace(attr(n, "lang")) == "" {
+		findings = append(findings, NewFinding(MISSING_LANG, WARNING, link, "the html element has no lang attribute"))
+	}
+
+	// images without alt text, an empty alt marks a decorative image
+	for _, n := range elements(doc, "img") {
+		if !hasAttr(n, "alt") {
+			findings = append(findings, NewFinding(
+				MISSING_ALT,
+				WARNING,
+				link,
+				fmt.Sprintf("the image %s has no alt text", attr(n, "src")),
+			))
+		}
+	}
+	return findings
+}
+
+// Returns the titles and descriptions shared by more than one page, sorted by the
+// number of pages that share them.
+func (c *seoCheck) Duplicates() []Duplicate {
+	duplicates := []Duplicate{}
+	for _, group := range []struct {
+		kind   string
+		values map[string]Set[string, int]
+	}{
+		{DUPLICATE_TITLE, c.titles},
+		{DUPLICATE_DESCRIPTION, c.descriptions},
+	} {
+		for value, pages := range group.values {
+			if len(pages) > 1 {
+				duplicates = append(duplicates, Duplicate{Kind: group.kind, Value: value, URLs: ordered(pages)})
+			}
+		}
+	}
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		a, b := duplicates[i], duplicates[j]
+		if len(a.URLs) != len(b.URLs) {
+			return len(a.URLs) > len(b.URLs)
+		}
+		if a.Kind != b.Kind {
+			return a.Kind > b.Kind
+		}
+		return a.Value < b.Value
+	})
+	return duplicates
+}
+
+// Adds the page at link to the pages with value, unless it is already one of them.
+func add(values map[string]Set[string, int], value string, link string) {
+	pages, found := values[value]
+	if !found {
+		pages = Set[string, int]{}
+		values[value] = pages
+	}
+	if !pages.Contains(link) {
+		pages[link] = len(pages)
+	}
+}
+
+// Returns the URLs of pages in the order they were found in.
+func ordered(pages Set[string, int]) []string {
+	urls := make([]string, 0, len(pages))
+	for link := range pages {
+		urls = append(urls, link)
+	}
+	sort.Slice(urls, func(i, j int) bool { return pages[urls[i]] < pages[urls[j]] })
+	return urls
+}
+
+// Returns a finding of kind for the text of an element named name if its length is
+// not between min and max characters.
+func length(kind string, link string, name string, text string, min int, max int) (Finding, bool) {
+	n := utf8.RuneCountInString(text)
+	switch {
+	case n < min:
+		return NewFinding(kind, NOTICE, link, fmt.Sprintf(
+			"the %s is %d characters, shorter than %d", name, n, min,
+		)), true
+	case n > max:
+		return NewFinding(kind, WARNING, link, fmt.Sprintf(
+			"the %s is %d characters, longer than %d", name, n, max,
+		)), true
+	}
+	return Finding{}, false
+}
+
+// Returns the robots directives in value, such as noindex and nofollow. A
+// directive for a specific crawler, such as "googlebot: noindex", applies to it.
+func directives(value string) []string {
+	found := []string{}
+	for _, d := range strings.Split(strings.ToLower(value), ",") {
+		d = strings.TrimSpace(d)
+		if agent, rest, ok := strings.Cut(d, ":"); ok && !strings.Contains(agent, " ") {
+			d = strings.TrimSpace(rest)
+		}
+		switch d {
+		case "all":
+			found = append(found, "index", "follow")
+		case "none":
+			found = append(found, "noindex", "nofollow")
+		case "":
+		default:
+			found = append(found, d)
+		}
+	}
+	return found
+}
+
+// Returns the first pair of directives that contradict each other, or an empty
+// string.
+func conflicting(directives []string) string {
+	set := Set[string, int]{}
+	for _, d := range directives {
+		set[d] = 0
+	}
+	for _, c := range robotsConflicts {
+		if set.Contains(c[0]) && set.Contains(c[1]) {
+			return fmt.Sprintf("both %s and %s", c[0], c[1])
+		}
+	}
+	return ""
+}
+
+// Returns every element named tag in the tree rooted at n.
+func elements(n *html.Node, tag string) []*html.Node {
+	found := []*html.Node{}
+	if n.Type == html.ElementNode && n.Data == tag {
+		found = append(found, n)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		found = append(found, elements(c, tag)...)
+	}
+	return found
+}
+
+// Returns the text in the tree rooted at n.
+func text(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(text(c))
+	}
+	return b.String()
+}
+
+// Returns s with each run of whitespace replaced by a single space.
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Returns true if element n has the attribute key.
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -1,4 +1,4 @@
-package main
+package linkt
 
 // The keys of this map represent a Set, i.e. no duplicate values.
 type Set[K comparable, V any] map[K]V
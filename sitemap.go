@@ -1,4 +1,4 @@
-package main
+package linkt
 
 import (
 	"encoding/xml"
@@ -60,7 +60,10 @@ func (s *Sitemap) String() string {
 		}
 	}
 
-	// begin preorderIndent with the tree's root
+	// begin preorderIndent with the tree's root, if the tree is not empty
+	if s.Root() == nil {
+		return str
+	}
 	preorderIndent(s, s.Root(), -1)
 	return str
 }
@@ -71,17 +74,15 @@ func (s *Sitemap) Print() {
 }
 
 // Writes each link in the sitemap to an XML file and stores that file at directory dir.
-func (s *Sitemap) XML(dir string) {
+func (s *Sitemap) XML(dir string) error {
 	// create sitemap XML file
 	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-		s.logger.Error("directory not found", "error", err)
-		os.Exit(0)
+		return fmt.Errorf("directory not found: %w", err)
 	}
 	path := filepath.Join(dir, "sitemap.xml")
 	file, err := os.Create(path)
 	if err != nil {
-		s.logger.Error("sitemap file not created", "error", err)
-		os.Exit(0)
+		return fmt.Errorf("sitemap file not created: %w", err)
 	}
 	defer file.Close()
 
@@ -96,16 +97,20 @@ func (s *Sitemap) XML(dir string) {
 	file.WriteString("\n")
 	s.Preorder(func(c *Node[Page]) {
 		e := url{Link: c.GetElement().request.URL.String()}
-		if !allLinks.Contains(e) {
+		if !allLinks.Contains(e) && err == nil {
 			allLinks[e] = 0
-			data, err := xml.MarshalIndent(e, "", "  ")
-			if err != nil {
-				s.logger.Error("could not marshal link to XML", "error", err)
-				os.Exit(0)
-			}
+			var data []byte
+			data, err = xml.MarshalIndent(e, "", "  ")
 			file.Write(data)
 			file.WriteString("\n")
 		}
 	})
-	file.WriteString("</urlset>")
+	if err != nil {
+		return fmt.Errorf("could not marshal link to XML: %w", err)
+	}
+	if _, err := file.WriteString("</urlset>"); err != nil {
+		return err
+	}
+	s.logger.Info("wrote the sitemap", "path", path)
+	return nil
 }
@@ -0,0 +1,213 @@
+package linkt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// The number of pages the crawler visits between saving its state.
+const checkpointEvery = 25
+
+// A page in the sitemap of a saved crawl.
+type SavedPage struct {
+	URL       string `json:"url"`
+	Kind      int    `json:"kind"`
+	ParentURL string `json:"parentURL"`
+	// index of the parent of the page in the saved pages, or -1 for the root
+	Parent int `json:"parent"`
+}
+
+// The progress of a crawl, saved so the crawl can be resumed after it is
+// interrupted.
+type State struct {
+	Command string    `json:"command"`
+	Root    string    `json:"root"`
+	Saved   time.Time `json:"saved"`
+	// pages in the sitemap in preorder, so a parent comes before its children
+	Pages []SavedPage `json:"pages"`
+	// indexes of the pages that were found but not visited yet
+	Frontier  []int            `json:"frontier"`
+	Visited   Set[string, int] `json:"visited"`
+	Inspected Set[string, int] `json:"inspected"`
+	Records   []Record         `json:"records"`
+	// screenshots taken so far, and the results of comparing them against a baseline
+	Screenshots []Entry      `json:"screenshots,omitempty"`
+	Comparisons []Comparison `json:"comparisons,omitempty"`
+	// pages with each title and meta description, for an SEO audit
+	Titles       map[string][]string `json:"titles,omitempty"`
+	Descriptions map[string][]string `json:"descriptions,omitempty"`
+	// fingerprints of the content of the pages, for an SEO audit
+	Fingerprints []Fingerprint `json:"fingerprints,omitempty"`
+	// metrics of the pages measured so far, for a performance report
+	Metrics []Metrics `json:"metrics,omitempty"`
+}
+
+// Returns the state saved in the file at path.
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	state := &State{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("invalid crawl state in %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// Writes the state to the file at path. The state is written to a temporary file
+// first, so an interruption while saving does not corrupt the previous state.
+func (s *State) Save(path string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return err
+		}
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0666); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Saves the state of the crawl to the file specified with the state option.
+func (crawler *Crawler) checkpoint() {
+	if crawler.options.State == "" {
+		return
+	}
+	state := &State{
+		Command:     crawler.options.Action,
+		Root:        crawler.sitemap.Root().GetElement().request.URL.String(),
+		Saved:       time.Now().UTC(),
+		Pages:       []SavedPage{},
+		Frontier:    []int{},
+		Visited:     *crawler.visited,
+		Inspected:   *crawler.inspected,
+		Records:     crawler.records,
+		Screenshots: crawler.manifest.Entries,
+		Comparisons: crawler.comparisons,
+		Metrics:     crawler.metrics,
+	}
+	if crawler.seo != nil {
+		state.Titles = map[string][]string{}
+		state.Descriptions = map[string][]string{}
+		for value, pages := range crawler.seo.titles {
+			state.Titles[value] = ordered(pages)
+		}
+		for value, pages := range crawler.seo.descriptions {
+			state.Descriptions[value] = ordered(pages)
+		}
+	}
+	if crawler.content != nil {
+		state.Fingerprints = crawler.content.fingerprints
+	}
+	index := map[*Node[Page]]int{}
+	crawler.sitemap.Preorder(func(n *Node[Page]) {
+		parent := -1
+		if n.GetParent() != nil {
+			parent = index[n.GetParent()]
+		}
+		index[n] = len(state.Pages)
+		page := n.GetElement()
+		state.Pages = append(state.Pages, SavedPage{
+			URL:       page.request.URL.String(),
+			Kind:      page.kind,
+			ParentURL: page.parentURL,
+			Parent:    parent,
+		})
+	})
+	for _, n := range crawler.frontier {
+		state.Frontier = append(state.Frontier, index[n])
+	}
+	if err := state.Save(crawler.options.State); err != nil {
+		crawler.logger.Error("error saving the crawl state", "path", crawler.options.State, "error", err)
+		return
+	}
+	crawler.logger.Info(
+		"saved the crawl state",
+		"path", crawler.options.State,
+		"pages", len(state.Pages),
+		"remaining", len(state.Frontier),
+	)
+}
+
+// Restores the sitemap, frontier, visited links, and results of the crawl of root
+// from state.
+func (crawler *Crawler) restore(state *State, root *url.URL) error {
+	if state.Command != crawler.options.Action || state.Root != root.String() {
+		return fmt.Errorf(
+			"the saved crawl is for %s %s, not %s %s",
+			state.Command, state.Root, crawler.options.Action, root.String(),
+		)
+	}
+	if len(state.Pages) == 0 {
+		return errors.New("the saved crawl has no pages")
+	}
+	nodes := make([]*Node[Page], len(state.Pages))
+	for i, p := range state.Pages {
+		link, err := url.Parse(p.URL)
+		if err != nil {
+			return fmt.Errorf("invalid page in the saved crawl: %w", err)
+		}
+		page := *NewPage(link)
+		page.kind = p.Kind
+		page.parentURL = p.ParentURL
+		switch {
+		case i == 0 && p.Parent == -1:
+			nodes[i], err = crawler.sitemap.AddRoot(page)
+			if err != nil {
+				return err
+			}
+		case p.Parent >= 0 && p.Parent < i:
+			nodes[i] = crawler.sitemap.AddChild(nodes[p.Parent], page)
+		default:
+			return fmt.Errorf("invalid parent of page %s in the saved crawl", p.URL)
+		}
+	}
+	for _, i := range state.Frontier {
+		if i < 0 || i >= len(nodes) {
+			return fmt.Errorf("invalid page %d in the frontier of the saved crawl", i)
+		}
+		crawler.frontier = append(crawler.frontier, nodes[i])
+	}
+	if state.Visited != nil {
+		*crawler.visited = state.Visited
+	}
+	if state.Inspected != nil {
+		*crawler.inspected = state.Inspected
+	}
+	if state.Records != nil {
+		crawler.records = state.Records
+	}
+	crawler.manifest.Entries = append(crawler.manifest.Entries, state.Screenshots...)
+	crawler.comparisons = append(crawler.comparisons, state.Comparisons...)
+	crawler.metrics = append(crawler.metrics, state.Metrics...)
+	if crawler.seo != nil {
+		for value, urls := range state.Titles {
+			for _, link := range urls {
+				add(crawler.seo.titles, value, link)
+			}
+		}
+		for value, urls := range state.Descriptions {
+			for _, link := range urls {
+				add(crawler.seo.descriptions, value, link)
+			}
+		}
+	}
+	if crawler.content != nil {
+		for _, f := range state.Fingerprints {
+			crawler.content.fingerprints = append(crawler.content.fingerprints, f)
+			crawler.content.seen[f.URL] = 0
+		}
+	}
+	return nil
+}
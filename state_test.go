@@ -0,0 +1,83 @@
+package linkt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// A handler that cancels the crawl after a number of pages are visited.
+type interrupter struct {
+	NopHandler
+	cancel func()
+	after  int
+}
+
+func (h *interrupter) Visited(page Page) {
+	if h.after--; h.after == 0 {
+		h.cancel()
+	}
+}
+
+func TestResume(t *testing.T) {
+	pages := map[string]string{
+		"/":  `<a href="/a">a</a><a href="/b">b</a>`,
+		"/a": `<a href="/c">c</a><a href="/b">b</a>`,
+		"/b": `<a href="/d">d</a>`,
+		"/c": ``,
+		"/d": `<a href="/">home</a>`,
+	}
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, "<title>Page</title>%s", pages[r.URL.Path])
+	}))
+	defer server.Close()
+	root, _ := url.Parse(server.URL)
+
+	for _, action := range []string{TEST, SEO} {
+		t.Run(action, func(t *testing.T) {
+			_, want := crawl(t, NewOptions(action), server.URL)
+			clear(requests)
+
+			// interrupt the crawl after each number of pages and resume it
+			for after := 1; after < len(pages); after++ {
+				options := NewOptions(action)
+				options.State = filepath.Join(t.TempDir(), "state.json")
+				ctx, cancel := context.WithCancel(context.Background())
+				crawler, err := NewCrawler(options, &interrupter{cancel: cancel, after: after})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := crawler.Crawl(ctx, root); !errors.Is(err, context.Canceled) {
+					t.Fatalf("interrupted crawl returned %v, want %v", err, context.Canceled)
+				}
+				cancel()
+				options.Resume = true
+				resumed, got := crawl(t, options, server.URL)
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("stopped after %d pages: tested %v, want %v", after, got, want)
+				}
+				for p, n := range requests {
+					if n != 1 {
+						t.Errorf("stopped after %d pages: %s was requested %d times, want once", after, p, n)
+					}
+				}
+				clear(requests)
+				if action == SEO {
+					d := resumed.Duplicates()
+					if len(d) != 1 || len(d[0].URLs) != len(pages) {
+						t.Errorf("stopped after %d pages: duplicates = %v, want the title of every page", after, d)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package linkt
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Returns the HTTP transport the crawler sends requests with. The transport trusts
+// the certificates in the CA bundle specified, or skips verifying certificates if
+// the insecure option is set. Requests are sent through the proxy specified, or
+// the proxy in the HTTP_PROXY and HTTPS_PROXY environment variables, and
+// connections to a host that is overridden with the resolve option are made to
+// the address specified for it.
+func NewTransport(options *Options) (*http.Transport, error) {
+	roots, err := NewRoots(options.CABundle)
+	if err != nil {
+		return nil, err
+	}
+	dial, err := NewDialer(options)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		RootCAs:            roots,
+		InsecureSkipVerify: options.Insecure,
+	}
+	transport.DialContext = dial
+	transport.TLSHandshakeTimeout = options.TLSTimeout
+	if options.Proxy != "" {
+		proxy, err := url.Parse(options.Proxy)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy %q", options.Proxy)
+		}
+		switch proxy.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("unsupported proxy scheme %q", proxy.Scheme)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	return transport, nil
+}
+
+// A function that makes a network connection.
+type Dial func(ctx context.Context, network string, addr string) (net.Conn, error)
+
+// Returns a function that connects to an address with the dial timeout. Each
+// override specified with the resolve option is in the form <host>:<port>:<address>,
+// and connections to host and port are made to address instead, like curl's
+// --resolve.
+func NewDialer(options *Options) (Dial, error) {
+	overrides := map[string]string{}
+	for _, r := range options.Resolve {
+		parts := strings.SplitN(r, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("invalid resolve %q, expected <host>:<port>:<address>", r)
+		}
+		addr := strings.Trim(parts[2], "[]")
+		if net.ParseIP(addr) == nil {
+			return nil, fmt.Errorf("invalid address %q in resolve %q", parts[2], r)
+		}
+		overrides[net.JoinHostPort(strings.ToLower(parts[0]), parts[1])] = net.JoinHostPort(addr, parts[1])
+	}
+	dialer := &net.Dialer{Timeout: options.DialTimeout}
+	return func(ctx context.Context, network string, addr string) (net.Conn, error) {
+		if override, found := overrides[strings.ToLower(addr)]; found {
+			addr = override
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}, nil
+}
+
+// Returns the system roots along with the certificates in the PEM file at path. If
+// path is empty, then nil is returned so the system roots are used.
+func NewRoots(path string) (*x509.CertPool, error) {
+	if path == "" {
+		return nil, nil
+	}
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading CA bundle: %w", err)
+	}
+	if !roots.AppendCertsFromPEM(data) {
+		return nil, errors.New("the CA bundle does not contain a PEM certificate")
+	}
+	return roots, nil
+}